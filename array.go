@@ -1,23 +1,21 @@
 package json5extract
 
-import (
-	"io"
-	"unicode"
-)
+import "unicode"
+
+func parseArray(r *reader) (*JSON5, error) {
+	defer r.leave()
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
 
-func parseArray(r reader) (*JSON5, error) {
-	arr := &JSON5{kind: Array}
-	arr.push('[')
 	vals := make([]*JSON5, 0)
+	arr := &JSON5{kind: Array, val: vals}
+	arr.push('[')
 
 	for {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			if err == io.EOF {
-				return nil, ErrInvalidFormat
-			}
-
-			return nil, err
+			return nil, r.readErr(err, "value or ']'")
 		}
 
 		if unicode.IsControl(char) || char == ' ' {
@@ -32,7 +30,7 @@ func parseArray(r reader) (*JSON5, error) {
 		// comment
 		if char == '/' {
 			if _, err := parseComment(r); err != nil {
-				return nil, ErrInvalidFormat
+				return nil, err
 			}
 
 			continue
@@ -44,12 +42,16 @@ func parseArray(r reader) (*JSON5, error) {
 		}
 
 		if json5 != nil {
+			if err := checkMembers(r, len(vals)); err != nil {
+				return nil, err
+			}
+
 			vals = append(vals, json5)
 			arr.pushRns(json5.Runes())
 			break
 		}
 
-		return nil, ErrInvalidFormat
+		return nil, r.syntaxErr(CodeUnexpectedChar, "value or ']'")
 	}
 
 	onNext := false
@@ -57,11 +59,7 @@ func parseArray(r reader) (*JSON5, error) {
 	for {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			if err == io.EOF {
-				return nil, ErrInvalidFormat
-			}
-
-			return nil, err
+			return nil, r.readErr(err, "',' or ']'")
 		}
 
 		if unicode.IsControl(char) || char == ' ' {
@@ -70,7 +68,7 @@ func parseArray(r reader) (*JSON5, error) {
 
 		if char == ',' {
 			if onNext {
-				return nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeUnexpectedChar, "value or ']'")
 			}
 
 			arr.push(',')
@@ -86,7 +84,7 @@ func parseArray(r reader) (*JSON5, error) {
 		// comment
 		if char == '/' {
 			if _, err := parseComment(r); err != nil {
-				return nil, ErrInvalidFormat
+				return nil, err
 			}
 
 			continue
@@ -99,18 +97,31 @@ func parseArray(r reader) (*JSON5, error) {
 			}
 
 			if json != nil {
+				if err := checkMembers(r, len(vals)); err != nil {
+					return nil, err
+				}
+
 				arr.pushRns(json.raw)
 				vals = append(vals, json)
 				onNext = false
 				continue
 			}
 
-			return nil, ErrInvalidFormat
+			return nil, r.syntaxErr(CodeUnexpectedChar, "value or ']'")
 		}
 
-		return nil, ErrInvalidFormat
+		return nil, r.syntaxErr(CodeMissingComma, "',' or ']'")
 	}
 
 	arr.val = vals
 	return arr, nil
 }
+
+// checkMembers check if another member can be added to count members
+func checkMembers(r *reader, count int) error {
+	if max := r.opts.Limits.MaxMembers; max > 0 && count >= max {
+		return r.limitErr("MaxMembers", max)
+	}
+
+	return nil
+}
@@ -7,17 +7,17 @@ var (
 	boolTrue = []rune("rue")
 )
 
-func parseTrueBool(r reader) (*JSON5, error) {
+func parseTrueBool(r *reader) (*JSON5, error) {
 	bl := &JSON5{kind: Boolean}
 	bl.push('t')
 	for _, c := range boolTrue {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			return nil, err
+			return nil, r.readErr(err, "true")
 		}
 
 		if char != c {
-			return nil, ErrInvalidFormat
+			return nil, r.syntaxErr(CodeInvalidLiteral, "true")
 		}
 
 		bl.push(char)
@@ -28,17 +28,17 @@ func parseTrueBool(r reader) (*JSON5, error) {
 	return bl, nil
 }
 
-func parseFalseBool(r reader) (*JSON5, error) {
+func parseFalseBool(r *reader) (*JSON5, error) {
 	bl := &JSON5{kind: Boolean}
 	bl.push('f')
 	for _, c := range boolFalse {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			return nil, ErrInvalidFormat
+			return nil, r.readErr(err, "false")
 		}
 
 		if char != c {
-			return nil, ErrInvalidFormat
+			return nil, r.syntaxErr(CodeInvalidLiteral, "false")
 		}
 
 		bl.push(char)
@@ -0,0 +1,160 @@
+package json5extract
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"unicode/utf16"
+)
+
+// Canonical write the value in JSON Canonicalization Scheme (RFC 8785), which is the same for
+// equal values regardless of quoting, key order, whitespace and number spelling. Object keys are
+// sorted by UTF-16 code units and numbers are written as ECMAScript does for float64. NaN,
+// Infinity, numbers overflowing float64 and duplicate keys are an error
+func (json *JSON5) Canonical() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := writeCanonical(buf, json); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Hash return SHA-256 of the canonical form, see Canonical
+func (json *JSON5) Hash() ([sha256.Size]byte, error) {
+	b, err := json.Canonical()
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+
+	return sha256.Sum256(b), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, json *JSON5) error {
+	switch json.kind {
+	case String:
+		writeCanonicalStr(buf, json.String())
+	case Integer, Float:
+		f, err := json.Number().Float64()
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(canonicalNumber(f))
+	case Infinity, NaN:
+		return nonFiniteErr(json)
+	case Boolean, Null:
+		buf.WriteString(string(json.raw))
+	case Array:
+		buf.WriteByte('[')
+		for i, val := range json.Array() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeCanonical(buf, val); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+	case Object:
+		members := json.Members()
+		keys := make([][]uint16, len(members))
+		order := make([]int, len(members))
+		for i, m := range members {
+			keys[i] = utf16.Encode([]rune(m.Key))
+			order[i] = i
+		}
+
+		sort.SliceStable(order, func(i, j int) bool {
+			return compareUTF16(keys[order[i]], keys[order[j]]) < 0
+		})
+
+		buf.WriteByte('{')
+		for i, x := range order {
+			m := members[x]
+			if i > 0 {
+				prev := members[order[i-1]]
+				if prev.Key == m.Key {
+					return &DuplicateKeyError{Key: m.Key, First: prev.Value.key.start, Pos: m.Value.key.start}
+				}
+
+				buf.WriteByte(',')
+			}
+
+			writeCanonicalStr(buf, m.Key)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, m.Value); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte('}')
+	}
+
+	return nil
+}
+
+func compareUTF16(a, b []uint16) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+// writeCanonicalStr write str escaping only quote, reverse solidus and control chars
+func writeCanonicalStr(buf *bytes.Buffer, str string) {
+	buf.WriteByte('"')
+	for _, c := range str {
+		switch c {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(c)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, c)
+				continue
+			}
+
+			buf.WriteRune(c)
+		}
+	}
+
+	buf.WriteByte('"')
+}
+
+// canonicalNumber format finite float like ECMAScript Number.prototype.toString
+func canonicalNumber(f float64) string {
+	if f == 0 {
+		// negative zero too
+		return "0"
+	}
+
+	str := formatFloat(f, 64)
+
+	// ECMAScript write exponent without leading zero, such as 1e-7 instead of 1e-07
+	if n := len(str); n >= 4 && str[n-4] == 'e' && str[n-3] == '-' && str[n-2] == '0' {
+		str = str[:n-2] + str[n-1:]
+	}
+
+	return str
+}
@@ -0,0 +1,79 @@
+package json5extract
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCanonicalNumber(t *testing.T) {
+	// RFC 8785 appendix B
+	tests := []struct {
+		bits uint64
+		want string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"},
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+
+	for _, test := range tests {
+		if got := canonicalNumber(math.Float64frombits(test.bits)); got != test.want {
+			t.Errorf("%016x: got %s, want %s", test.bits, got, test.want)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		// keys sorted by UTF-16 code units, so the non BMP key sort before U+FB33
+		{`{"\u20ac": "Euro Sign", "\r": "Carriage Return", "\ufb33": "Hebrew Letter Dalet With Dagesh",
+			"1": "One", "\ud83d\ude00": "Emoji: Grinning Face", "\u0080": "Control", "\u00f6": "Latin Small Letter O With Diaeresis"}`,
+			"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\"," +
+				"\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}"},
+		{`{b: [0x10, +.5, 5., -0, 1E2], a: 'x\'y', 'a\u0000': null}`, `{"a":"x'y","a\u0000":null,"b":[16,0.5,5,0,100]}`},
+		{`["\u001f\/\t", true]`, `["\u001f/\t",true]`},
+	}
+
+	for _, test := range tests {
+		json := mustParse(t, []byte(test.in))
+		got, err := json.Canonical()
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.in, err)
+			continue
+		}
+
+		if string(got) != test.want {
+			t.Errorf("%s: got %s, want %s", test.in, got, test.want)
+		}
+	}
+
+	for _, in := range []string{`NaN`, `[Infinity]`, `1e400`} {
+		if _, err := mustParse(t, []byte(in)).Canonical(); err == nil {
+			t.Errorf("%s: expected error", in)
+		}
+	}
+}
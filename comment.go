@@ -1,30 +1,45 @@
 package json5extract
 
-import "io"
+import (
+	"io"
+	"unicode/utf8"
+)
 
-// comment types
+// Comment kinds
 const (
-	commInline = iota
-	commMultiLine
+	// CommentLine is a single line comment, such as // note
+	CommentLine = iota
+	// CommentBlock is a multi line comment, such as /* note */
+	CommentBlock
 )
 
-func parseComment(r reader) (int, error) {
+// Comment is a comment found in the source
+type Comment struct {
+	Kind int
+	// Text is the comment without its delimiters and line terminator
+	Text string
+	// Start is position of the first char, End is position right after the last char
+	Start Position
+	End   Position
+}
+
+// parseComment parse comment beginning with '/', which is the last rune read, and record it in r
+func parseComment(r *reader) (int, error) {
+	start := r.lastPos()
 	char, _, err := r.ReadRune()
 	if err != nil {
-		if err == io.EOF {
-			return 0, ErrInvalidFormat
-		}
-
-		return 0, err
+		return 0, r.readErr(err, "'/' or '*'")
 	}
 
 	// single line comment
 	if char == '/' {
+		end := r.pos()
 		for {
 			char, _, err := r.ReadRune()
 			if err != nil {
+				// single line comment may end the input
 				if err == io.EOF {
-					return 0, ErrInvalidFormat
+					break
 				}
 
 				return 0, err
@@ -33,9 +48,13 @@ func parseComment(r reader) (int, error) {
 			if char == '\r' || char == '\n' {
 				break
 			}
+
+			end = r.pos()
 		}
 
-		return commInline, nil
+		r.addComment(CommentLine, start, end)
+
+		return CommentLine, nil
 	}
 
 	// multi line comment
@@ -43,31 +62,200 @@ func parseComment(r reader) (int, error) {
 		for {
 			char, _, err := r.ReadRune()
 			if err != nil {
-				if err == io.EOF {
-					return 0, ErrInvalidFormat
-				}
-
-				return 0, err
+				return 0, r.readErr(err, "'*/'")
 			}
 
 			if char == '*' {
 				char, _, err := r.ReadRune()
 				if err != nil {
-					if err == io.EOF {
-						return 0, ErrInvalidFormat
-					}
-
-					return 0, err
+					return 0, r.readErr(err, "'*/'")
 				}
 
 				if char == '/' {
 					break
 				}
+
+				// '*' may be followed by the closing '*/'
+				if char == '*' {
+					r.UnreadRune()
+				}
+			}
+		}
+
+		r.addComment(CommentBlock, start, r.pos())
+
+		return CommentBlock, nil
+	}
+
+	return 0, r.syntaxErr(CodeInvalidComment, "'/' or '*'")
+}
+
+// attachComments attach comments to json and its descendants. A comment is attached to the
+// innermost array or object containing it: before the comma following an element, or on the same
+// line right after it, it is a trailing comment of the element, before an element it is a leading
+// comment of the element,
+// otherwise it is a dangling comment of the array or object. Comments before the root are leading
+// comments of it and comments after the root are trailing comments of it
+func attachComments(json *JSON5, comments []Comment) {
+	var inner []Comment
+	for _, c := range comments {
+		switch {
+		case c.End.Offset <= json.start.Offset:
+			set := json.commentSet()
+			set.leading = append(set.leading, c)
+		case c.Start.Offset >= json.end.Offset:
+			set := json.commentSet()
+			set.trailing = append(set.trailing, c)
+		default:
+			inner = append(inner, c)
+		}
+	}
+
+	attachInner(json, inner)
+}
+
+// attachInner attach comments located inside array or object json
+func attachInner(json *JSON5, comments []Comment) {
+	if len(comments) == 0 {
+		return
+	}
+
+	if json.kind != Array && json.kind != Object {
+		return
+	}
+
+	// in source order, members are not with some duplicate key policies
+	elems := elements(json)
+
+	// comments inside every element, by element index
+	nested := make(map[int][]Comment)
+	for _, c := range comments {
+		// index of the first element beginning after the comment
+		next := len(elems)
+		for i, e := range elems {
+			if elemStart(e).Offset >= c.End.Offset {
+				next = i
+				break
+			}
+		}
+
+		// inside the previous element, or between its key and value
+		if next > 0 {
+			prev := elems[next-1]
+			if c.Start.Offset < prev.end.Offset {
+				if c.Start.Offset >= prev.start.Offset {
+					nested[next-1] = append(nested[next-1], c)
+				} else {
+					set := prev.commentSet()
+					set.leading = append(set.leading, c)
+				}
+
+				continue
+			}
+		}
+
+		switch {
+		case next > 0 && c.End.Offset <= sepAfter(json, elems[next-1], comments):
+			set := elems[next-1].commentSet()
+			set.trailing = append(set.trailing, c)
+		case next > 0 && c.Start.Line == elems[next-1].end.Line &&
+			(next == len(elems) || elemStart(elems[next]).Line > c.End.Line):
+			set := elems[next-1].commentSet()
+			set.trailing = append(set.trailing, c)
+		case next < len(elems):
+			set := elems[next].commentSet()
+			set.leading = append(set.leading, c)
+		default:
+			set := json.commentSet()
+			set.dangling = append(set.dangling, c)
+		}
+	}
+
+	for i, cs := range nested {
+		attachInner(elems[i], cs)
+	}
+}
+
+// sepAfter return offset of the comma following element elem of array or object json, or -1 if
+// there is none. Only whitespaces and comments, which are inside json, may be before the comma
+func sepAfter(json, elem *JSON5, comments []Comment) int {
+	i := elem.end.Offset
+	for i < json.end.Offset {
+		char, size := utf8.DecodeRune(json.src[i-json.start.Offset:])
+		switch {
+		case char == ',':
+			return i
+		case isWhitespace(char):
+			i += size
+		case char == '/':
+			end := -1
+			for _, c := range comments {
+				if c.Start.Offset == i {
+					end = c.End.Offset
+					break
+				}
+			}
+
+			if end < 0 {
+				return -1
 			}
+
+			i = end
+		default:
+			return -1
 		}
+	}
+
+	return -1
+}
+
+// elemStart return position of array element or object member, which begin at its key
+func elemStart(json *JSON5) Position {
+	if json.key != nil {
+		return json.key.start
+	}
+
+	return json.start
+}
+
+// nodeComments are comments attached to a value
+type nodeComments struct {
+	leading, trailing, dangling []Comment
+}
+
+// commentSet return comments of the value, allocating them if needed
+func (json *JSON5) commentSet() *nodeComments {
+	if json.comments == nil {
+		json.comments = new(nodeComments)
+	}
+
+	return json.comments
+}
+
+// LeadingComments return comments before the value, such as doc comments of an object member
+func (json *JSON5) LeadingComments() []Comment {
+	if json.comments == nil {
+		return nil
+	}
+
+	return json.comments.leading
+}
+
+// TrailingComments return comments after the value on the same line
+func (json *JSON5) TrailingComments() []Comment {
+	if json.comments == nil {
+		return nil
+	}
+
+	return json.comments.trailing
+}
 
-		return commMultiLine, nil
+// DanglingComments return comments inside array or object which are not attached to any of its
+// elements, such as comments of an empty object
+func (json *JSON5) DanglingComments() []Comment {
+	if json.comments == nil {
+		return nil
 	}
 
-	return 0, ErrInvalidFormat
+	return json.comments.dangling
 }
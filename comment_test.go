@@ -0,0 +1,100 @@
+package json5extract
+
+import (
+	"fmt"
+	"testing"
+)
+
+// commentsOf describe comments attached to json as leading|trailing|dangling texts
+func commentsOf(json *JSON5) string {
+	texts := func(cs []Comment) []string {
+		strs := make([]string, len(cs))
+		for i, c := range cs {
+			strs[i] = c.Text
+		}
+
+		return strs
+	}
+
+	return fmt.Sprintf("%q|%q|%q", texts(json.LeadingComments()), texts(json.TrailingComments()), texts(json.DanglingComments()))
+}
+
+func TestComments(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		opts *Options
+		// comments by JSON Pointer of the value
+		want map[string]string
+	}{
+		{"root", "// lead\n/* block */ [1] // trail\n/* end */", nil, map[string]string{
+			"":   `[" lead" " block "]|[" trail" " end "]|[]`,
+			"/0": `[]|[]|[]`,
+		}},
+		{"array", "[\n  // one\n  1, // after one\n  2 /* two */, 3,\n  // end\n]", nil, map[string]string{
+			"":   `[]|[]|[" end"]`,
+			"/0": `[" one"]|[" after one"]|[]`,
+			"/1": `[]|[" two "]|[]`,
+			"/2": `[]|[]|[]`,
+		}},
+		{"array single line", `[1 /* a */, /* b */ 2, 3 /* c */]`, nil, map[string]string{
+			"/0": `[]|[" a "]|[]`,
+			"/1": `[" b "]|[]|[]`,
+			"/2": `[]|[" c "]|[]`,
+		}},
+		{"comment before comma on next line", "[\n  1\n  // one\n  , 2\n]", nil, map[string]string{
+			"/0": `[]|[" one"]|[]`,
+			"/1": `[]|[]|[]`,
+		}},
+		{"object", "{\n  // a doc\n  a: 1 /* a */, b: /* in */ 2, // b\n  c: {} // c\n}", nil, map[string]string{
+			"":   `[]|[]|[]`,
+			"/a": `[" a doc"]|[" a "]|[]`,
+			"/b": `[" in "]|[" b"]|[]`,
+			"/c": `[]|[" c"]|[]`,
+		}},
+		{"comma inside comment", `{a: 1 /* x, y */ /* z */, b: 2}`, nil, map[string]string{
+			"/a": `[]|[" x, y " " z "]|[]`,
+			"/b": `[]|[]|[]`,
+		}},
+		{"empty", "{ /* empty */ }", nil, map[string]string{
+			"": `[]|[]|[" empty "]`,
+		}},
+		{"nested", "[[1 /* x */, 2], {a: [/* none */]}]", nil, map[string]string{
+			"/0/0": `[]|[" x "]|[]`,
+			"/0/1": `[]|[]|[]`,
+			"/1/a": `[]|[]|[" none "]`,
+			"/1":   `[]|[]|[]`,
+			"/0":   `[]|[]|[]`,
+			"":     `[]|[]|[]`,
+		}},
+		{"duplicate keep last", "{\n  // b doc\n  b: 1,\n  a: 2, // two\n  // a doc\n  a: 3 // three\n}", &Options{DuplicateKeys: DuplicateKeepLast}, map[string]string{
+			"/b": `[" b doc"]|[]|[]`,
+			// comments of the dropped member are before the kept one
+			"/a": `[" two" " a doc"]|[" three"]|[]`,
+		}},
+		{"duplicate keep last before other member", `{a: 1, /* b doc */ b: 2, a: 3 /* three */}`, &Options{DuplicateKeys: DuplicateKeepLast}, map[string]string{
+			"/b": `[" b doc "]|[]|[]`,
+			"/a": `[]|[" three "]|[]`,
+		}},
+	}
+
+	for _, test := range tests {
+		json, err := Parse([]byte(test.in), test.opts)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+
+		for ptr, want := range test.want {
+			val, err := json.Pointer(ptr)
+			if err != nil {
+				t.Errorf("%s: %q: %v", test.name, ptr, err)
+				continue
+			}
+
+			if got := commentsOf(val); got != want {
+				t.Errorf("%s: %q: got %s, want %s", test.name, ptr, got, want)
+			}
+		}
+	}
+}
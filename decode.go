@@ -0,0 +1,437 @@
+package json5extract
+
+import (
+	"encoding"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	json5PtrType       = reflect.TypeOf((*JSON5)(nil))
+	numberType         = reflect.TypeOf(Number{})
+	bigIntType         = reflect.TypeOf(big.Int{})
+	bigFloatType       = reflect.TypeOf(big.Float{})
+	textUnmarshalerTyp = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+var kindNames = map[int]string{
+	String:   "string",
+	Integer:  "integer",
+	Float:    "float",
+	Infinity: "Infinity",
+	NaN:      "NaN",
+	Boolean:  "boolean",
+	Null:     "null",
+	Array:    "array",
+	Object:   "object",
+}
+
+// DecodeError occured when a value can not be stored in a Go value
+type DecodeError struct {
+	// Path is the path of the value from the decoded value, such as items[3].id
+	Path string
+	// Pos is position of the value in the source
+	Pos Position
+	// Kind is kind of the value
+	Kind int
+	// Type is the Go type the value can not be stored in
+	Type reflect.Type
+	// Err is the underlying error, such as range error of a number. May be nil
+	Err error
+}
+
+func (err *DecodeError) Error() string {
+	path := err.Path
+	if path == "" {
+		path = "(root)"
+	}
+
+	msg := fmt.Sprintf("cannot decode %s into Go value of type %s at %s (line %d, column %d)",
+		kindNames[err.Kind], err.Type, path, err.Pos.Line, err.Pos.Column)
+	if err.Err != nil {
+		msg += ": " + err.Err.Error()
+	}
+
+	return msg
+}
+
+// Unwrap return the underlying error
+func (err *DecodeError) Unwrap() error {
+	return err.Err
+}
+
+// Unmarshal parse data as a single JSON5 document and decode it into v, see Parse and JSON5.Decode
+func Unmarshal(data []byte, v interface{}) error {
+	json, err := Parse(data)
+	if err != nil {
+		return err
+	}
+
+	return json.Decode(v)
+}
+
+// Decode store the value in v, which must be a non nil pointer.
+//
+// Object members are stored in struct fields named by json5 tag, falling back to json tag
+// and then field name, a key matching no field exactly is matched case insensitively.
+// Unknown keys are ignored. Maps must have string, integer or encoding.TextUnmarshaler keys.
+// Numbers can be stored in any Go numeric type, Number, big.Int and big.Float. Strings are
+// also stored using encoding.TextUnmarshaler. A *JSON5 target receive the value itself.
+// Null set pointers, interfaces, maps and slices to nil and leave other values unchanged.
+//
+// In interface{}, values are stored as string, int64 (Number if it overflows int64), float64,
+// bool, nil, []interface{} and map[string]interface{}
+func (json *JSON5) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("json5extract: Decode(%v), target must be a non nil pointer", reflect.TypeOf(v))
+	}
+
+	return decodeValue(json, rv.Elem(), "")
+}
+
+func typeErr(json *JSON5, v reflect.Value, path string, err error) error {
+	return &DecodeError{Path: path, Pos: json.start, Kind: json.kind, Type: v.Type(), Err: err}
+}
+
+// pathKey return path of object member key from path of object
+func pathKey(path, key string) string {
+	if isIdentifierName(key) {
+		if path == "" {
+			return key
+		}
+
+		return path + "." + key
+	}
+
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+// pathIndex return path of array element i from path of array
+func pathIndex(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+func decodeValue(json *JSON5, v reflect.Value, path string) error {
+	if v.Type() == json5PtrType {
+		v.Set(reflect.ValueOf(json))
+		return nil
+	}
+
+	if json.kind == Null {
+		switch v.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+		}
+
+		return nil
+	}
+
+	if v.Kind() == reflect.Interface {
+		if v.NumMethod() > 0 {
+			return typeErr(json, v, path, nil)
+		}
+
+		v.Set(reflect.ValueOf(genericValue(json)))
+		return nil
+	}
+
+	// allocate pointers
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		v = v.Elem()
+	}
+
+	switch json.kind {
+	case String:
+		return decodeStr(json, v, path)
+	case Integer, Float, Infinity, NaN:
+		return decodeNum(json, v, path)
+	case Boolean:
+		if v.Kind() != reflect.Bool {
+			return typeErr(json, v, path, nil)
+		}
+
+		v.SetBool(json.Boolean())
+	case Array:
+		return decodeArray(json, v, path)
+	case Object:
+		return decodeObj(json, v, path)
+	}
+
+	return nil
+}
+
+func decodeStr(json *JSON5, v reflect.Value, path string) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerTyp) {
+		u := v.Addr().Interface().(encoding.TextUnmarshaler)
+		if err := u.UnmarshalText([]byte(json.String())); err != nil {
+			return typeErr(json, v, path, err)
+		}
+
+		return nil
+	}
+
+	if v.Kind() != reflect.String {
+		return typeErr(json, v, path, nil)
+	}
+
+	v.SetString(json.String())
+
+	return nil
+}
+
+func decodeNum(json *JSON5, v reflect.Value, path string) error {
+	num := json.Number()
+	switch v.Type() {
+	case numberType:
+		v.Set(reflect.ValueOf(num))
+		return nil
+	case bigIntType:
+		i, err := num.BigInt()
+		if err != nil {
+			return typeErr(json, v, path, err)
+		}
+
+		v.Set(reflect.ValueOf(i).Elem())
+		return nil
+	case bigFloatType:
+		f, err := num.BigFloat()
+		if err != nil {
+			return typeErr(json, v, path, err)
+		}
+
+		v.Set(reflect.ValueOf(f).Elem())
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := num.Int64()
+		if err == nil && v.OverflowInt(i) {
+			err = &strconv.NumError{Func: "Int64", Num: num.String(), Err: strconv.ErrRange}
+		}
+
+		if err != nil {
+			return typeErr(json, v, path, err)
+		}
+
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := num.Uint64()
+		if err == nil && v.OverflowUint(u) {
+			err = &strconv.NumError{Func: "Uint64", Num: num.String(), Err: strconv.ErrRange}
+		}
+
+		if err != nil {
+			return typeErr(json, v, path, err)
+		}
+
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := num.Float64()
+		if err == nil && json.kind != Infinity && v.OverflowFloat(f) {
+			err = &strconv.NumError{Func: "Float64", Num: num.String(), Err: strconv.ErrRange}
+		}
+
+		if err != nil {
+			return typeErr(json, v, path, err)
+		}
+
+		v.SetFloat(f)
+	default:
+		return typeErr(json, v, path, nil)
+	}
+
+	return nil
+}
+
+func decodeArray(json *JSON5, v reflect.Value, path string) error {
+	vals := json.Array()
+	switch v.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(v.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := decodeValue(val, slice.Index(i), pathIndex(path, i)); err != nil {
+				return err
+			}
+		}
+
+		v.Set(slice)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if i >= len(vals) {
+				v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+				continue
+			}
+
+			if err := decodeValue(vals[i], v.Index(i), pathIndex(path, i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return typeErr(json, v, path, nil)
+	}
+
+	return nil
+}
+
+func decodeObj(json *JSON5, v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := typeFields(v.Type())
+		for _, m := range json.Members() {
+			f := findField(fields, m.Key)
+			if f == nil {
+				continue
+			}
+
+			fv, err := fieldByIndex(v, f.index)
+			if err != nil {
+				return typeErr(m.Value, v, pathKey(path, m.Key), err)
+			}
+
+			if err := decodeValue(m.Value, fv, pathKey(path, m.Key)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		t := v.Type()
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(t))
+		}
+
+		for _, m := range json.Members() {
+			key, err := mapKey(t.Key(), m.Key)
+			if err != nil {
+				return typeErr(m.Value.key, reflect.New(t.Key()).Elem(), pathKey(path, m.Key), err)
+			}
+
+			elem := reflect.New(t.Elem()).Elem()
+			if err := decodeValue(m.Value, elem, pathKey(path, m.Key)); err != nil {
+				return err
+			}
+
+			v.SetMapIndex(key, elem)
+		}
+	default:
+		return typeErr(json, v, path, nil)
+	}
+
+	return nil
+}
+
+// findField return field with name, or the first one matching name case insensitively
+func findField(fields []field, name string) *field {
+	for i := range fields {
+		if fields[i].name == name {
+			return &fields[i]
+		}
+	}
+
+	for i := range fields {
+		if strings.EqualFold(fields[i].name, name) {
+			return &fields[i]
+		}
+	}
+
+	return nil
+}
+
+// fieldByIndex return nested field, allocating nil embedded struct pointers
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("cannot set embedded pointer to unexported struct %v", v.Type().Elem())
+				}
+
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, nil
+}
+
+// mapKey convert object key to map key of type t
+func mapKey(t reflect.Type, key string) (reflect.Value, error) {
+	if reflect.PtrTo(t).Implements(textUnmarshalerTyp) {
+		k := reflect.New(t)
+		if err := k.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+			return reflect.Value{}, err
+		}
+
+		return k.Elem(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(key).Convert(t), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(i).Convert(t), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(key, 10, t.Bits())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(u).Convert(t), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("unsupported map key type %v", t)
+}
+
+// genericValue return value stored in interface{}
+func genericValue(json *JSON5) interface{} {
+	switch json.kind {
+	case String:
+		return json.String()
+	case Integer:
+		i, err := json.Number().Int64()
+		if err != nil {
+			return json.Number()
+		}
+
+		return i
+	case Float, Infinity, NaN:
+		f, _ := json.Number().Float64()
+		return f
+	case Boolean:
+		return json.Boolean()
+	case Array:
+		vals := json.Array()
+		arr := make([]interface{}, len(vals))
+		for i, val := range vals {
+			arr[i] = genericValue(val)
+		}
+
+		return arr
+	case Object:
+		members := json.Members()
+		obj := make(map[string]interface{}, len(members))
+		for _, m := range members {
+			obj[m.Key] = genericValue(m.Value)
+		}
+
+		return obj
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package json5extract
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type decTagged struct {
+	Name  string `json5:"name"`
+	Age   int    `json:"age"`
+	Both  int    `json5:"b5" json:"bj"`
+	Skip  string `json5:"-"`
+	Plain bool
+}
+
+type decBase struct {
+	ID   int
+	Name string
+}
+
+type DecExtra struct {
+	Extra string
+}
+
+type decEmbed struct {
+	decBase
+	*DecExtra
+	Name string
+}
+
+// decUpper store text in upper case, and reject empty text
+type decUpper string
+
+func (u *decUpper) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errors.New("empty text")
+	}
+
+	*u = decUpper(strings.ToUpper(string(text)))
+
+	return nil
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		in string
+		// pointer to the decoded value
+		ptr  interface{}
+		want interface{}
+	}{
+		// struct tags
+		{`{name: 'a', age: 3, b5: 1, bj: 2, Skip: 'x', plain: true}`, new(decTagged),
+			decTagged{Name: "a", Age: 3, Both: 1, Plain: true}},
+		{`{NAME: 'a', unknown: [1, {}]}`, new(decTagged), decTagged{Name: "a"}},
+
+		// embedded fields, the outer field hide the promoted one
+		{`{ID: 7, Name: 'outer', Extra: 'e'}`, new(decEmbed),
+			decEmbed{decBase: decBase{ID: 7}, DecExtra: &DecExtra{Extra: "e"}, Name: "outer"}},
+		{`{ID: 7}`, new(decEmbed), decEmbed{decBase: decBase{ID: 7}}},
+
+		// TextUnmarshaler
+		{`'abc'`, new(decUpper), decUpper("ABC")},
+		{`['a', "b"]`, new([]decUpper), []decUpper{"A", "B"}},
+
+		// map keys
+		{`{a: 1, 'b c': 2}`, new(map[string]int), map[string]int{"a": 1, "b c": 2}},
+		{`{'-1': 'x', '20': 'y'}`, new(map[int8]string), map[int8]string{-1: "x", 20: "y"}},
+		{`{'255': true}`, new(map[uint8]bool), map[uint8]bool{255: true}},
+		{`{a: 1, b: 2}`, new(map[decUpper]int), map[decUpper]int{"A": 1, "B": 2}},
+
+		// numbers
+		{`[0x10, +1, -2, 1e2]`, new([]int), []int{16, 1, -2, 100}},
+		{`[.5, 5., Infinity, -Infinity]`, new([4]float64), [4]float64{0.5, 5, math.Inf(1), math.Inf(-1)}},
+		{`[1, 2, 3]`, new([2]uint), [2]uint{1, 2}},
+		{`[1]`, new([2]uint), [2]uint{1, 0}},
+
+		// pointers and null
+		{`{a: 1}`, new(*struct{ A *int }), &struct{ A *int }{A: intPtr(1)}},
+		{`{a: null}`, new(map[string]*int), map[string]*int{"a": nil}},
+		{`null`, &[]int{1}, []int(nil)},
+		{`null`, &decTagged{Name: "a"}, decTagged{Name: "a"}},
+
+		// interface{}
+		{`{a: [1, 1.5, 'x', true, null], b: {}}`, new(interface{}),
+			map[string]interface{}{
+				"a": []interface{}{int64(1), 1.5, "x", true, nil},
+				"b": map[string]interface{}{},
+			}},
+	}
+
+	for _, test := range tests {
+		if err := Unmarshal([]byte(test.in), test.ptr); err != nil {
+			t.Errorf("%s: unexpected error %v", test.in, err)
+			continue
+		}
+
+		if got := reflect.ValueOf(test.ptr).Elem().Interface(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %#v, want %#v", test.in, got, test.want)
+		}
+	}
+
+	// integer overflowing int64 is stored as Number
+	var v interface{}
+	if err := Unmarshal([]byte(`99999999999999999999`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if num, ok := v.(Number); !ok || num.String() != "99999999999999999999" {
+		t.Errorf("got %#v, want Number 99999999999999999999", v)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		ptr  interface{}
+		path string
+		line int
+		col  int
+		// underlying error, nil for a kind mismatch
+		err error
+	}{
+		{`'x'`, new(int), "", 1, 1, nil},
+		{`{a: [1, true]}`, new(struct{ A []int }), "a[1]", 1, 9, nil},
+		{"{\n  a: {\n    b: 300\n  }\n}", new(struct{ A struct{ B int8 } }), "a.b", 3, 8, strconv.ErrRange},
+		{`[-1]`, new([]uint), "[0]", 1, 2, strconv.ErrRange},
+		{`[1.5]`, new([]int), "[0]", 1, 2, nil},
+		{`{'b c': 1e400}`, new(map[string]float32), `["b c"]`, 1, 9, strconv.ErrRange},
+		{`{x: 1}`, new(map[int]int), "x", 1, 2, strconv.ErrSyntax},
+		{`{'': 1}`, new(map[decUpper]int), `[""]`, 1, 2, nil},
+		{`['']`, new([]decUpper), "[0]", 1, 2, nil},
+		{`{a: 1}`, new([]int), "", 1, 1, nil},
+		{`[1]`, new(fmt.Stringer), "", 1, 1, nil},
+	}
+
+	for _, test := range tests {
+		err := Unmarshal([]byte(test.in), test.ptr)
+		var decErr *DecodeError
+		if !errors.As(err, &decErr) {
+			t.Errorf("%s: got %v, want DecodeError", test.in, err)
+			continue
+		}
+
+		if decErr.Path != test.path || decErr.Pos.Line != test.line || decErr.Pos.Column != test.col {
+			t.Errorf("%s: got error at %s (%d:%d), want at %s (%d:%d)", test.in,
+				decErr.Path, decErr.Pos.Line, decErr.Pos.Column, test.path, test.line, test.col)
+		}
+
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Errorf("%s: got %v, want %v", test.in, err, test.err)
+		}
+	}
+
+	// target must be a non nil pointer
+	for _, v := range []interface{}{nil, 1, (*int)(nil)} {
+		if err := Unmarshal([]byte(`1`), v); err == nil {
+			t.Errorf("%#v: expected error", v)
+		}
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,498 @@
+package json5extract
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Document is a JSON5 document kept as its source bytes. Set, Delete and Insert rewrite only the
+// span of the affected value, so comments, whitespace, quote styles and key order elsewhere are
+// reproduced exactly by Bytes. Values are addressed by JSON Pointer, see JSON5.Pointer
+type Document struct {
+	src  []byte
+	root *JSON5
+	opts *Options
+	// Encoding is used to write values which are set or inserted, nil means compact JSON5
+	Encoding *MarshalOptions
+}
+
+// patch replace src[start:end] with text
+type patch struct {
+	start, end int
+	text       string
+}
+
+// ParseDocument parse data as a single JSON5 document, see Parse. Only the first non nil opts is used,
+// it is also used to parse the document after every edit
+func ParseDocument(data []byte, opts ...*Options) (*Document, error) {
+	doc := &Document{src: append([]byte(nil), data...), opts: optionsOf(opts)}
+	root, err := Parse(doc.src, doc.opts)
+	if err != nil {
+		return nil, err
+	}
+
+	doc.root = root
+
+	return doc, nil
+}
+
+// Root return parsed value of the document. It is replaced after every edit
+func (doc *Document) Root() *JSON5 {
+	return doc.root
+}
+
+// Bytes return the document source with every edit applied
+func (doc *Document) Bytes() []byte {
+	return append([]byte(nil), doc.src...)
+}
+
+// Set replace value located by ptr with v encoded by Marshal. If ptr locate a missing member of an
+// object, or index equal to array length, or "-" in an array, v is inserted instead, see Insert
+func (doc *Document) Set(ptr string, v interface{}) error {
+	node, err := doc.root.Pointer(ptr)
+	if err != nil {
+		parent, token, perr := doc.parentOf(ptr)
+		if perr != nil {
+			return err
+		}
+
+		switch parent.kind {
+		case Object:
+			return doc.Insert(ptr, v)
+		case Array:
+			if token == "-" || token == strconv.Itoa(len(parent.Array())) {
+				return doc.Insert(ptr, v)
+			}
+		}
+
+		return err
+	}
+
+	text, err := doc.encode(v)
+	if err != nil {
+		return err
+	}
+
+	text = doc.indented(text, node.start.Offset)
+
+	return doc.apply([]patch{{start: node.start.Offset, end: node.end.Offset, text: text}})
+}
+
+// Delete remove value located by ptr from its array or object, together with its separating comma and
+// its leading and trailing comments. Lines left empty are removed. In an object, every member with the
+// key is removed, including duplicate members dropped by Options.DuplicateKeys. The root can not be deleted
+func (doc *Document) Delete(ptr string) error {
+	src, root := doc.src, doc.root
+	for deleted := false; ; deleted = true {
+		node, err := doc.root.Pointer(ptr)
+		if err != nil {
+			if deleted && errors.Is(err, ErrNotFound) {
+				return nil
+			}
+
+			doc.src, doc.root = src, root
+			return err
+		}
+
+		if err := doc.delete(ptr, node); err != nil {
+			doc.src, doc.root = src, root
+			return err
+		}
+
+		// a duplicate member may be visible once the kept one is removed
+		if node.parent.kind != Object {
+			return nil
+		}
+	}
+}
+
+// delete remove node located by ptr, see Delete
+func (doc *Document) delete(ptr string, node *JSON5) error {
+	parent := node.parent
+	if parent == nil {
+		return fmt.Errorf("json5extract: Delete(%q), the root can not be deleted", ptr)
+	}
+
+	elems := elements(parent)
+	i := 0
+	for elems[i] != node {
+		i++
+	}
+
+	start := node.start.Offset
+	if lead := node.LeadingComments(); len(lead) > 0 && lead[0].Start.Offset < start {
+		start = lead[0].Start.Offset
+	}
+
+	if node.key != nil && node.key.start.Offset < start {
+		start = node.key.start.Offset
+	}
+
+	end := node.end.Offset
+	comma, hasComma := doc.commaAfter(end)
+	if hasComma {
+		end = comma + 1
+	}
+
+	if trail := node.TrailingComments(); len(trail) > 0 {
+		if e := trail[len(trail)-1].End.Offset; e > end {
+			end = e
+		}
+	}
+
+	// never remove the comma after the previous element together with the node
+	var prevComma int
+	if i > 0 {
+		prevComma, _ = doc.commaAfter(elems[i-1].end.Offset)
+		if start <= prevComma {
+			start = prevComma + 1
+		}
+	}
+
+	if hasComma || i == 0 {
+		start, end = doc.extendSpan(start, end)
+		return doc.apply([]patch{{start: start, end: end}})
+	}
+
+	// last element, remove the comma after the previous one but keep its trailing comments
+	patches := []patch{{start: prevComma, end: prevComma + 1}}
+	cut := patch{start: prevComma + 1, end: end}
+	if trail := elems[i-1].TrailingComments(); len(trail) > 0 && trail[len(trail)-1].End.Offset > cut.start {
+		last := trail[len(trail)-1]
+		cut.start = last.End.Offset
+		// a line comment must still be ended by a line break
+		if last.Kind == CommentLine && !doc.blankUntilLineEnd(end) {
+			cut.text = doc.lineBreak() + doc.indentOf(parent.start.Offset)
+		}
+	}
+
+	return doc.apply(append(patches, cut))
+}
+
+// Insert insert v encoded by Marshal at ptr. In an array, the last token of ptr is the index v is
+// inserted at, which may be the array length or "-" to append. In an object, the last token is the
+// key of the member appended to the object, which must not exist. The layout of neighbour elements,
+// single line or one element per line, is followed
+func (doc *Document) Insert(ptr string, v interface{}) error {
+	parent, token, err := doc.parentOf(ptr)
+	if err != nil {
+		return err
+	}
+
+	text, err := doc.encode(v)
+	if err != nil {
+		return err
+	}
+
+	if parent.kind != Array && parent.kind != Object {
+		return &PointerError{Pointer: ptr, Resolved: parent.Path().Pointer(), Kind: parent.kind, Pos: parent.start,
+			Msg: fmt.Sprintf("cannot insert %q into %s", token, kindNames[parent.kind])}
+	}
+
+	elems := elements(parent)
+	k := len(elems)
+	if parent.kind == Array {
+		if token != "-" {
+			idx, ok := arrayIndex(token)
+			if !ok || idx > len(elems) {
+				return &PointerError{Pointer: ptr, Resolved: parent.Path().Pointer(), Kind: Array, Pos: parent.start,
+					Msg: fmt.Sprintf("index %s out of range of array of length %d", token, len(elems))}
+			}
+
+			k = idx
+		}
+	} else {
+		if _, ok := parent.Get(token); ok {
+			return fmt.Errorf("json5extract: Insert(%q), key already exists", ptr)
+		}
+
+		text = doc.keyText(parent, token) + ": " + text
+	}
+
+	return doc.apply(doc.insertPatches(parent, elems, k, text))
+}
+
+// insertPatches return patches inserting element text at index k of container
+func (doc *Document) insertPatches(container *JSON5, elems []*JSON5, k int, text string) []patch {
+	if len(elems) == 0 {
+		pos := container.start.Offset + 1
+		return []patch{{start: pos, end: pos, text: doc.indented(text, pos)}}
+	}
+
+	// before element k, and before its leading comments
+	if k < len(elems) {
+		pos := leadStart(elems[k])
+		text = doc.indented(text, pos)
+		if doc.firstOnLine(pos) {
+			return []patch{{start: pos, end: pos, text: text + "," + doc.lineBreak() + doc.indentOf(pos)}}
+		}
+
+		return []patch{{start: pos, end: pos, text: text + doc.separator(elems, k)}}
+	}
+
+	// after the last element
+	last := elems[len(elems)-1]
+	text = doc.indented(text, leadStart(last))
+	comma, hasComma := doc.commaAfter(last.end.Offset)
+	pos := last.end.Offset
+	if hasComma {
+		pos = comma + 1
+	}
+
+	if !doc.firstOnLine(leadStart(last)) {
+		sep := doc.separator(elems, k)
+		if hasComma {
+			return []patch{{start: pos, end: pos, text: sep[1:] + text + ","}}
+		}
+
+		return []patch{{start: pos, end: pos, text: sep + text}}
+	}
+
+	if trail := last.TrailingComments(); len(trail) > 0 && trail[len(trail)-1].End.Offset > pos {
+		pos = trail[len(trail)-1].End.Offset
+	}
+
+	if doc.blankUntilLineEnd(pos) {
+		pos = doc.lineEnd(pos)
+		// before the return carriage of CRLF
+		if pos > 0 && doc.src[pos-1] == '\r' {
+			pos--
+		}
+	}
+
+	line := doc.lineBreak() + doc.indentOf(leadStart(last)) + text
+	switch {
+	case hasComma:
+		return []patch{{start: pos, end: pos, text: line + ","}}
+	case pos == last.end.Offset:
+		return []patch{{start: pos, end: pos, text: "," + line}}
+	}
+
+	return []patch{
+		{start: last.end.Offset, end: last.end.Offset, text: ","},
+		{start: pos, end: pos, text: line},
+	}
+}
+
+// separator return comma and spaces separating elements of a single line container, copied from
+// the elements around index k, or ", " if they are separated by anything else
+func (doc *Document) separator(elems []*JSON5, k int) string {
+	i := k - 1
+	if i+1 >= len(elems) {
+		i = len(elems) - 2
+	}
+
+	if i < 0 {
+		i = 0
+	}
+
+	if i+1 >= len(elems) {
+		return ", "
+	}
+
+	comma, ok := doc.commaAfter(elems[i].end.Offset)
+	if !ok {
+		return ", "
+	}
+
+	sep := doc.src[comma:leadStart(elems[i+1])]
+	if len(bytes.Trim(sep[1:], " \t")) > 0 {
+		return ", "
+	}
+
+	return string(sep)
+}
+
+// indented return multi line text with every line after the first indented like the line of offset
+func (doc *Document) indented(text string, offset int) string {
+	return strings.Replace(text, "\n", doc.lineBreak()+doc.indentOf(offset), -1)
+}
+
+// parentOf return array or object containing the value located by ptr, and the last token of ptr
+func (doc *Document) parentOf(ptr string) (*JSON5, string, error) {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(tokens) == 0 {
+		return nil, "", fmt.Errorf("json5extract: %q locate the root, which has no parent", ptr)
+	}
+
+	parent, err := doc.root.Pointer(joinTokens(tokens[:len(tokens)-1]))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return parent, tokens[len(tokens)-1], nil
+}
+
+func (doc *Document) encode(v interface{}) (string, error) {
+	b, err := Marshal(v, doc.Encoding)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// keyText return key written like the first key of obj, quoted with double quotes if obj is empty
+func (doc *Document) keyText(obj *JSON5, key string) string {
+	quote := byte('"')
+	if members := obj.Members(); len(members) > 0 {
+		raw := members[0].Value.key.raw
+		switch {
+		case raw[0] == '\'':
+			quote = '\''
+		case raw[0] != '"' && isIdentifierName(key):
+			return key
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	writeQuoted(buf, key, quote)
+
+	return buf.String()
+}
+
+// apply apply patches to the source and parse it again, the document is unchanged on error
+func (doc *Document) apply(patches []patch) error {
+	sort.Slice(patches, func(i, j int) bool {
+		return patches[i].start > patches[j].start
+	})
+
+	src := append([]byte(nil), doc.src...)
+	for _, p := range patches {
+		src = append(src[:p.start], append([]byte(p.text), src[p.end:]...)...)
+	}
+
+	root, err := Parse(src, doc.opts)
+	if err != nil {
+		return fmt.Errorf("json5extract: edit produced invalid document: %w", err)
+	}
+
+	doc.src = src
+	doc.root = root
+
+	return nil
+}
+
+// commaAfter return offset of comma following offset, skipping whitespaces and comments
+func (doc *Document) commaAfter(offset int) (int, bool) {
+	t := NewTokenizer(bytes.NewReader(doc.src[offset:]))
+	for {
+		tok, err := t.Next()
+		if err != nil {
+			return 0, false
+		}
+
+		switch tok.Kind {
+		case TokenWhitespace, TokenComment:
+			continue
+		case TokenPunct:
+			if tok.Raw == "," {
+				return offset + tok.Start.Offset, true
+			}
+		}
+
+		return 0, false
+	}
+}
+
+// extendSpan extend span to whole lines if nothing else is on them, otherwise to following spaces,
+// or to preceding spaces if none follow
+func (doc *Document) extendSpan(start, end int) (int, int) {
+	lineStart := bytes.LastIndexByte(doc.src[:start], '\n') + 1
+	if doc.firstOnLine(start) && doc.blankUntilLineEnd(end) {
+		end = doc.lineEnd(end)
+		if end < len(doc.src) {
+			end++
+		}
+
+		return lineStart, end
+	}
+
+	spaced := end
+	for end < len(doc.src) && (doc.src[end] == ' ' || doc.src[end] == '\t') {
+		end++
+	}
+
+	if end == spaced {
+		for start > lineStart && (doc.src[start-1] == ' ' || doc.src[start-1] == '\t') {
+			start--
+		}
+	}
+
+	return start, end
+}
+
+// firstOnLine check if only spaces and tabs are before offset on its line
+func (doc *Document) firstOnLine(offset int) bool {
+	lineStart := bytes.LastIndexByte(doc.src[:offset], '\n') + 1
+	return len(bytes.TrimLeft(doc.src[lineStart:offset], " \t")) == 0
+}
+
+// blankUntilLineEnd check if only whitespaces are after offset on its line
+func (doc *Document) blankUntilLineEnd(offset int) bool {
+	return len(bytes.TrimSpace(doc.src[offset:doc.lineEnd(offset)])) == 0
+}
+
+// lineEnd return offset of line feed ending the line of offset, or length of source
+func (doc *Document) lineEnd(offset int) int {
+	if i := bytes.IndexByte(doc.src[offset:], '\n'); i >= 0 {
+		return offset + i
+	}
+
+	return len(doc.src)
+}
+
+// lineBreak return line terminator of the source, which is the one ending its first line
+func (doc *Document) lineBreak() string {
+	if i := bytes.IndexByte(doc.src, '\n'); i > 0 && doc.src[i-1] == '\r' {
+		return "\r\n"
+	}
+
+	return "\n"
+}
+
+// indentOf return spaces and tabs beginning the line of offset
+func (doc *Document) indentOf(offset int) string {
+	lineStart := bytes.LastIndexByte(doc.src[:offset], '\n') + 1
+	line := doc.src[lineStart:offset]
+
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+// leadStart return offset where element begins, its leading comments included
+func leadStart(json *JSON5) int {
+	start := elemStart(json).Offset
+	if lead := json.LeadingComments(); len(lead) > 0 && lead[0].Start.Offset < start {
+		start = lead[0].Start.Offset
+	}
+
+	return start
+}
+
+// elements return array elements or object member values in source order, which differ from member
+// order when the value of a duplicate key is kept at the position of the first member
+func elements(json *JSON5) []*JSON5 {
+	if json.kind == Array {
+		return json.Array()
+	}
+
+	members := json.Members()
+	elems := make([]*JSON5, len(members))
+	for i, m := range members {
+		elems[i] = m.Value
+	}
+
+	sort.Slice(elems, func(i, j int) bool {
+		return elemStart(elems[i]).Offset < elemStart(elems[j]).Offset
+	})
+
+	return elems
+}
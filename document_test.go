@@ -0,0 +1,290 @@
+package json5extract
+
+import (
+	"errors"
+	"testing"
+)
+
+const config = `// config
+{
+  // port doc
+  port: 8080, // default
+  host: 'x', /* h */
+  list: [1, 2, 3],
+  rows: [
+    1,
+    2
+  ],
+  empty: {},
+}
+`
+
+type editTest struct {
+	name string
+	src  string
+	edit func(doc *Document) error
+	want string
+}
+
+func runEditTests(t *testing.T, tests []editTest) {
+	for _, test := range tests {
+		doc, err := ParseDocument([]byte(test.src))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if err := test.edit(doc); err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+
+		if got := string(doc.Bytes()); got != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, got, test.want)
+			continue
+		}
+
+		// root is parsed from the edited source
+		if got := string(doc.Root().Original()); got != string(mustParse(t, doc.Bytes()).Original()) {
+			t.Errorf("%s: root is not up to date, got %s", test.name, got)
+		}
+	}
+}
+
+func mustParse(t *testing.T, data []byte) *JSON5 {
+	json, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return json
+}
+
+func TestDocumentUntouched(t *testing.T) {
+	doc, err := ParseDocument([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(doc.Bytes()) != config {
+		t.Errorf("got %q, want %q", doc.Bytes(), config)
+	}
+}
+
+func TestDocumentSet(t *testing.T) {
+	runEditTests(t, []editTest{
+		{"scalar keep comments", config, func(doc *Document) error { return doc.Set("/port", 9090) },
+			"// config\n{\n  // port doc\n  port: 9090, // default\n  host: 'x', /* h */\n  list: [1, 2, 3],\n  rows: [\n    1,\n    2\n  ],\n  empty: {},\n}\n"},
+		{"array element", `[1, 2, 3]`, func(doc *Document) error { return doc.Set("/1", "two") },
+			`[1, "two", 3]`},
+		{"container", `{a: [1, 2], b: 1}`, func(doc *Document) error { return doc.Set("/a", map[string]bool{"x": true}) },
+			`{a: {"x":true}, b: 1}`},
+		{"missing key insert", `{a: 1}`, func(doc *Document) error { return doc.Set("/b", 2) },
+			`{a: 1, b: 2}`},
+		{"array length append", `[1]`, func(doc *Document) error { return doc.Set("/1", 2) },
+			`[1, 2]`},
+		{"dash append", `[1]`, func(doc *Document) error { return doc.Set("/-", 2) },
+			`[1, 2]`},
+		{"root", "// c\n[1]\n", func(doc *Document) error { return doc.Set("", true) },
+			"// c\ntrue\n"},
+		{"encoding", `{a: 1}`, func(doc *Document) error {
+			doc.Encoding = &MarshalOptions{SingleQuote: true}
+			return doc.Set("/a", "x")
+		}, `{a: 'x'}`},
+		{"escaped key", `{"a/b": 1, "c~d": 2}`, func(doc *Document) error { return doc.Set("/c~0d", 3) },
+			`{"a/b": 1, "c~d": 3}`},
+	})
+}
+
+func TestDocumentDelete(t *testing.T) {
+	runEditTests(t, []editTest{
+		{"single line first", `[1, 2, 3]`, func(doc *Document) error { return doc.Delete("/0") },
+			`[2, 3]`},
+		{"single line middle", `[1, 2, 3]`, func(doc *Document) error { return doc.Delete("/1") },
+			`[1, 3]`},
+		{"single line last", `[1, 2, 3]`, func(doc *Document) error { return doc.Delete("/2") },
+			`[1, 2]`},
+		{"single line last trailing comma", `[1, 2,]`, func(doc *Document) error { return doc.Delete("/1") },
+			`[1,]`},
+		{"only element", `[1]`, func(doc *Document) error { return doc.Delete("/0") },
+			`[]`},
+		{"only member", `{ a: 1 }`, func(doc *Document) error { return doc.Delete("/a") },
+			`{ }`},
+		{"member with comments", config, func(doc *Document) error { return doc.Delete("/port") },
+			"// config\n{\n  host: 'x', /* h */\n  list: [1, 2, 3],\n  rows: [\n    1,\n    2\n  ],\n  empty: {},\n}\n"},
+		{"last member trailing comma", config, func(doc *Document) error { return doc.Delete("/empty") },
+			"// config\n{\n  // port doc\n  port: 8080, // default\n  host: 'x', /* h */\n  list: [1, 2, 3],\n  rows: [\n    1,\n    2\n  ],\n}\n"},
+		{"multi line last", config, func(doc *Document) error { return doc.Delete("/rows/1") },
+			"// config\n{\n  // port doc\n  port: 8080, // default\n  host: 'x', /* h */\n  list: [1, 2, 3],\n  rows: [\n    1\n  ],\n  empty: {},\n}\n"},
+		{"multi line first", config, func(doc *Document) error { return doc.Delete("/rows/0") },
+			"// config\n{\n  // port doc\n  port: 8080, // default\n  host: 'x', /* h */\n  list: [1, 2, 3],\n  rows: [\n    2\n  ],\n  empty: {},\n}\n"},
+		{"last keep previous trailing comment", "{\n  a: 1, // c\n  // b doc\n  b: 2 // d\n}",
+			func(doc *Document) error { return doc.Delete("/b") },
+			"{\n  a: 1 // c\n}"},
+		{"duplicate keys", `{a: 1, b: 2, a: 3}`, func(doc *Document) error { return doc.Delete("/a") },
+			`{b: 2}`},
+		{"nested", `{a: {b: [1, {c: 2, d: 3}]}}`, func(doc *Document) error { return doc.Delete("/a/b/1/c") },
+			`{a: {b: [1, {d: 3}]}}`},
+		{"previous block comment before comma", `[1 /* one */, 2, 3]`, func(doc *Document) error { return doc.Delete("/1") },
+			`[1 /* one */, 3]`},
+		{"previous member block comment before comma", `{a: 1 /* a */, b: 2, c: 3}`, func(doc *Document) error { return doc.Delete("/b") },
+			`{a: 1 /* a */, c: 3}`},
+		{"last after line comment", "[1, // x\n2]", func(doc *Document) error { return doc.Delete("/1") },
+			"[1 // x\n]"},
+		{"last member after line comment", "{a: 1, // note\n b: 2}", func(doc *Document) error { return doc.Delete("/b") },
+			"{a: 1 // note\n}"},
+		{"last after line comment before comma", "[1 // x\n, 2]", func(doc *Document) error { return doc.Delete("/1") },
+			"[1 // x\n]"},
+		{"last after line comment crlf", "[\r\n  1, // x\r\n  2]", func(doc *Document) error { return doc.Delete("/1") },
+			"[\r\n  1 // x\r\n]"},
+		{"multi line crlf", "{\r\n  a: 1,\r\n  b: 2,\r\n  c: 3\r\n}", func(doc *Document) error { return doc.Delete("/b") },
+			"{\r\n  a: 1,\r\n  c: 3\r\n}"},
+	})
+}
+
+func TestDocumentDeleteDuplicates(t *testing.T) {
+	for _, policy := range []int{DuplicateKeepLast, DuplicateKeepFirst, DuplicateKeepAll} {
+		doc, err := ParseDocument([]byte(`{a: 1, b: 2, a: 3, c: 4, a: 5}`), &Options{DuplicateKeys: policy})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := doc.Delete("/a"); err != nil {
+			t.Errorf("policy %d: unexpected error %v", policy, err)
+			continue
+		}
+
+		if got := string(doc.Bytes()); got != `{b: 2, c: 4}` {
+			t.Errorf("policy %d: got %s, want {b: 2, c: 4}", policy, got)
+		}
+	}
+}
+
+func TestDocumentInsert(t *testing.T) {
+	runEditTests(t, []editTest{
+		{"single line before", `[1, 3]`, func(doc *Document) error { return doc.Insert("/1", 2) },
+			`[1, 2, 3]`},
+		{"single line first", `[1, 3]`, func(doc *Document) error { return doc.Insert("/0", 0) },
+			`[0, 1, 3]`},
+		{"single line append", `[1, 2]`, func(doc *Document) error { return doc.Insert("/-", 3) },
+			`[1, 2, 3]`},
+		{"single line append trailing comma", `[1, 2,]`, func(doc *Document) error { return doc.Insert("/2", 3) },
+			`[1, 2, 3,]`},
+		{"empty array", `[]`, func(doc *Document) error { return doc.Insert("/-", 1) },
+			`[1]`},
+		{"empty object", `{}`, func(doc *Document) error { return doc.Insert("/a", 1) },
+			`{"a": 1}`},
+		{"multi line append", "[\n  1,\n  2\n]", func(doc *Document) error { return doc.Insert("/-", 3) },
+			"[\n  1,\n  2,\n  3\n]"},
+		{"multi line append trailing comma", "[\n  1,\n  2,\n]", func(doc *Document) error { return doc.Insert("/-", 3) },
+			"[\n  1,\n  2,\n  3,\n]"},
+		{"multi line append after comment", "{\n  a: 1 // c\n}", func(doc *Document) error { return doc.Insert("/b", 2) },
+			"{\n  a: 1, // c\n  b: 2\n}"},
+		{"multi line append closing on same line", "[\n  1,\n  2]", func(doc *Document) error { return doc.Insert("/-", 3) },
+			"[\n  1,\n  2,\n  3]"},
+		{"multi line before leading comment", "[\n  // one\n  1\n]", func(doc *Document) error { return doc.Insert("/0", 0) },
+			"[\n  0,\n  // one\n  1\n]"},
+		{"config member", config, func(doc *Document) error { return doc.Insert("/debug", false) },
+			"// config\n{\n  // port doc\n  port: 8080, // default\n  host: 'x', /* h */\n  list: [1, 2, 3],\n  rows: [\n    1,\n    2\n  ],\n  empty: {},\n  debug: false,\n}\n"},
+		{"single quoted key", `{'a': 1}`, func(doc *Document) error { return doc.Insert("/b", 2) },
+			`{'a': 1, 'b': 2}`},
+		{"double quoted key", `{"a": 1}`, func(doc *Document) error { return doc.Insert("/b", 2) },
+			`{"a": 1, "b": 2}`},
+		{"reserved key", `{a: 1}`, func(doc *Document) error { return doc.Insert("/new", 2) },
+			`{a: 1, "new": 2}`},
+		{"key not identifier", `{a: 1}`, func(doc *Document) error { return doc.Insert("/b c", 2) },
+			`{a: 1, "b c": 2}`},
+		{"crlf append", "{\r\n  a: 1,\r\n  b: 2\r\n}", func(doc *Document) error { return doc.Insert("/c", 3) },
+			"{\r\n  a: 1,\r\n  b: 2,\r\n  c: 3\r\n}"},
+		{"crlf append after comment", "{\r\n  a: 1 // c\r\n}", func(doc *Document) error { return doc.Insert("/b", 2) },
+			"{\r\n  a: 1, // c\r\n  b: 2\r\n}"},
+		{"crlf before", "[\r\n  1,\r\n  2\r\n]", func(doc *Document) error { return doc.Insert("/0", 0) },
+			"[\r\n  0,\r\n  1,\r\n  2\r\n]"},
+		{"indented value", "{\n  a: 1,\n}", func(doc *Document) error {
+			doc.Encoding = &MarshalOptions{Indent: "  "}
+			return doc.Insert("/b", map[string]int{"x": 1})
+		}, "{\n  a: 1,\n  b: {\n    \"x\": 1\n  },\n}"},
+		{"indented value nested", "{\n  a: [\n    1\n  ]\n}", func(doc *Document) error {
+			doc.Encoding = &MarshalOptions{Indent: "  "}
+			return doc.Insert("/a/0", []int{0})
+		}, "{\n  a: [\n    [\n      0\n    ],\n    1\n  ]\n}"},
+		{"indented value crlf", "[\r\n  1\r\n]", func(doc *Document) error {
+			doc.Encoding = &MarshalOptions{Indent: "\t"}
+			return doc.Insert("/-", []int{2})
+		}, "[\r\n  1,\r\n  [\r\n  \t2\r\n  ]\r\n]"},
+		{"copy separator before", `[1,2]`, func(doc *Document) error { return doc.Insert("/1", 9) },
+			`[1,9,2]`},
+		{"copy separator first", `[1,2]`, func(doc *Document) error { return doc.Insert("/0", 0) },
+			`[0,1,2]`},
+		{"copy separator append", `{a:1,b:2}`, func(doc *Document) error { return doc.Insert("/c", 3) },
+			`{a:1,b:2,c: 3}`},
+		{"copy separator append trailing comma", `[1,  2,]`, func(doc *Document) error { return doc.Insert("/-", 3) },
+			`[1,  2,  3,]`},
+		{"separator with comment", `[1, /* c */ 2]`, func(doc *Document) error { return doc.Insert("/-", 3) },
+			`[1, /* c */ 2, 3]`},
+	})
+}
+
+func TestDocumentErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(doc *Document) error
+	}{
+		{"delete root", func(doc *Document) error { return doc.Delete("") }},
+		{"delete missing", func(doc *Document) error { return doc.Delete("/missing") }},
+		{"set missing parent", func(doc *Document) error { return doc.Set("/missing/a", 1) }},
+		{"set index out of range", func(doc *Document) error { return doc.Set("/list/9", 1) }},
+		{"insert existing key", func(doc *Document) error { return doc.Insert("/port", 1) }},
+		{"insert index out of range", func(doc *Document) error { return doc.Insert("/list/4", 1) }},
+		{"insert into scalar", func(doc *Document) error { return doc.Insert("/port/a", 1) }},
+		{"insert root", func(doc *Document) error { return doc.Insert("", 1) }},
+		{"unsupported value", func(doc *Document) error { return doc.Set("/port", make(chan int)) }},
+		{"malformed pointer", func(doc *Document) error { return doc.Set("port", 1) }},
+	}
+
+	for _, test := range tests {
+		doc, err := ParseDocument([]byte(config))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := test.edit(doc); err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+
+		// document is unchanged on error
+		if string(doc.Bytes()) != config {
+			t.Errorf("%s: document changed to %q", test.name, doc.Bytes())
+		}
+	}
+
+	doc, _ := ParseDocument([]byte(config))
+	if err := doc.Delete("/missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
+
+func TestDocumentSequence(t *testing.T) {
+	doc, err := ParseDocument([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edits := []func() error{
+		func() error { return doc.Set("/host", "example.com") },
+		func() error { return doc.Delete("/list/0") },
+		func() error { return doc.Insert("/rows/1", 5) },
+		func() error { return doc.Set("/empty/k", true) },
+		func() error { return doc.Delete("/port") },
+	}
+
+	for i, edit := range edits {
+		if err := edit(); err != nil {
+			t.Fatalf("edit %d: %v", i, err)
+		}
+	}
+
+	want := "// config\n{\n  host: \"example.com\", /* h */\n  list: [2, 3],\n  rows: [\n    1,\n    5,\n    2\n  ],\n  empty: {\"k\": true},\n}\n"
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
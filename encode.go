@@ -0,0 +1,574 @@
+package json5extract
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MarshalOptions control JSON5 text written by Marshal
+type MarshalOptions struct {
+	// Indent is indentation of one nesting level, such as "  " or "\t". If empty,
+	// everything is written on a single line
+	Indent string
+	// SingleQuote write strings with single quotes instead of double quotes
+	SingleQuote bool
+	// UnquotedKeys write object keys which are valid identifiers without quotes
+	UnquotedKeys bool
+	// TrailingComma write comma after the last array element and object member,
+	// only when Indent is set
+	TrailingComma bool
+	// SpecialFloats write infinite and NaN floats as Infinity and NaN, otherwise they are an error
+	SpecialFloats bool
+}
+
+// UnsupportedValueError occured when a Go value can not be written as JSON5
+type UnsupportedValueError struct {
+	Value reflect.Value
+	Msg   string
+}
+
+func (err *UnsupportedValueError) Error() string {
+	return "json5extract: unsupported value: " + err.Msg
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	bigIntPtrType     = reflect.TypeOf((*big.Int)(nil))
+	bigFloatPtrType   = reflect.TypeOf((*big.Float)(nil))
+)
+
+// Marshal write v as JSON5 text. Only the first non nil opts is used.
+//
+// Struct fields are named by json5 tag, falling back to json tag and then field name, and
+// support omitempty option. Integer fields tagged with hex option, such as `json5:"id,hex"`,
+// are written in hexadecimal. Comment tag, such as `comment:"user id"`, is written as
+// comment before the member. Maps are written with sorted keys. Values implementing
+// encoding.TextMarshaler are written as string. Number, big.Int, big.Float and *JSON5 are
+// supported.
+func Marshal(v interface{}, opts ...*MarshalOptions) ([]byte, error) {
+	o := new(MarshalOptions)
+	for _, opt := range opts {
+		if opt != nil {
+			o = opt
+			break
+		}
+	}
+
+	e := &encodeState{opts: o}
+	if err := e.encode(reflect.ValueOf(v), nil); err != nil {
+		return nil, err
+	}
+
+	return e.buf.Bytes(), nil
+}
+
+type encodeState struct {
+	buf   bytes.Buffer
+	opts  *MarshalOptions
+	depth int
+	// pointers, maps and slices being written, to detect cycles
+	seen map[ref]bool
+}
+
+// ref identify what a pointer, map or slice refer to
+type ref struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter mark pointer, map or slice v as being written, return error if it is already being written.
+// leave must be called once v is written
+func (e *encodeState) enter(v reflect.Value) error {
+	key := ref{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+
+	if e.seen[key] {
+		return &UnsupportedValueError{Value: v, Msg: fmt.Sprintf("encountered a cycle via %s", v.Type())}
+	}
+
+	if e.seen == nil {
+		e.seen = make(map[ref]bool)
+	}
+
+	e.seen[key] = true
+
+	return nil
+}
+
+func (e *encodeState) leave(v reflect.Value) {
+	key := ref{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+
+	delete(e.seen, key)
+}
+
+// newline write line break and indentation of current depth
+func (e *encodeState) newline() {
+	if e.opts.Indent == "" {
+		return
+	}
+
+	e.buf.WriteByte('\n')
+	for i := 0; i < e.depth; i++ {
+		e.buf.WriteString(e.opts.Indent)
+	}
+}
+
+// writeList write n elements between open and close, write is called for every element
+func (e *encodeState) writeList(open, close byte, n int, write func(i int) error) error {
+	e.buf.WriteByte(open)
+	if n == 0 {
+		e.buf.WriteByte(close)
+		return nil
+	}
+
+	e.depth++
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+
+		e.newline()
+		if err := write(i); err != nil {
+			return err
+		}
+	}
+
+	if e.opts.TrailingComma && e.opts.Indent != "" {
+		e.buf.WriteByte(',')
+	}
+
+	e.depth--
+	e.newline()
+	e.buf.WriteByte(close)
+
+	return nil
+}
+
+// writeComment write comment before an element
+func (e *encodeState) writeComment(comment string) {
+	if e.opts.Indent == "" {
+		e.buf.WriteString("/* ")
+		e.buf.WriteString(strings.ReplaceAll(comment, "*/", "* /"))
+		e.buf.WriteString(" */ ")
+		return
+	}
+
+	for _, line := range strings.Split(comment, "\n") {
+		e.buf.WriteString("// ")
+		e.buf.WriteString(line)
+		e.newline()
+	}
+}
+
+// writeStr write quoted string
+func (e *encodeState) writeStr(str string) {
+	quote := byte('"')
+	if e.opts.SingleQuote {
+		quote = '\''
+	}
+
+	writeQuoted(&e.buf, str, quote)
+}
+
+// writeKey write object key followed by colon
+func (e *encodeState) writeKey(key string) {
+	if e.opts.UnquotedKeys && isIdentifierName(key) {
+		e.buf.WriteString(key)
+	} else {
+		e.writeStr(key)
+	}
+
+	e.buf.WriteByte(':')
+	if e.opts.Indent != "" {
+		e.buf.WriteByte(' ')
+	}
+}
+
+// writeQuoted write str between quote, escaping quote, reverse solidus, control chars
+// and line terminators
+func writeQuoted(buf *bytes.Buffer, str string, quote byte) {
+	buf.WriteByte(quote)
+	for _, c := range str {
+		switch {
+		case c == rune(quote) || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(c)
+		case c == '\b':
+			buf.WriteString(`\b`)
+		case c == '\f':
+			buf.WriteString(`\f`)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case c < 0x20 || c == 0x7f || c == '\u2028' || c == '\u2029':
+			fmt.Fprintf(buf, `\u%04x`, c)
+		default:
+			buf.WriteRune(c)
+		}
+	}
+
+	buf.WriteByte(quote)
+}
+
+// formatFloat format finite float like encoding/json does
+func formatFloat(f float64, bits int) string {
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 && (bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21)) {
+		format = 'e'
+	}
+
+	return strconv.FormatFloat(f, format, -1, bits)
+}
+
+func (e *encodeState) writeFloat(v reflect.Value, f float64, bits int) error {
+	switch {
+	case math.IsInf(f, 1):
+		if !e.opts.SpecialFloats {
+			return &UnsupportedValueError{Value: v, Msg: "+Inf"}
+		}
+
+		e.buf.WriteString("Infinity")
+	case math.IsInf(f, -1):
+		if !e.opts.SpecialFloats {
+			return &UnsupportedValueError{Value: v, Msg: "-Inf"}
+		}
+
+		e.buf.WriteString("-Infinity")
+	case math.IsNaN(f):
+		if !e.opts.SpecialFloats {
+			return &UnsupportedValueError{Value: v, Msg: "NaN"}
+		}
+
+		e.buf.WriteString("NaN")
+	default:
+		e.buf.WriteString(formatFloat(f, bits))
+	}
+
+	return nil
+}
+
+// encode write v, f is the struct field holding v if any
+func (e *encodeState) encode(v reflect.Value, f *field) error {
+	if !v.IsValid() {
+		e.buf.WriteString("null")
+		return nil
+	}
+
+	switch v.Type() {
+	case json5PtrType:
+		if v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+
+		return e.encodeJSON5(v.Interface().(*JSON5))
+	case numberType:
+		num := v.Interface().(Number)
+		if (num.kind == Infinity || num.kind == NaN) && !e.opts.SpecialFloats {
+			return &UnsupportedValueError{Value: v, Msg: num.String()}
+		}
+
+		// zero Number has no literal
+		if num.lit == "" {
+			e.buf.WriteString("0")
+			return nil
+		}
+
+		e.buf.WriteString(num.String())
+		return nil
+	case bigIntType, bigFloatType:
+		// written as number through pointer, not as string through TextMarshaler
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr
+	case bigIntPtrType, bigFloatPtrType:
+		if v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+	}
+
+	switch x := v.Interface().(type) {
+	case *big.Int:
+		e.buf.WriteString(x.String())
+		return nil
+	case *big.Float:
+		if x.IsInf() {
+			return e.writeFloat(v, math.Inf(x.Sign()), 64)
+		}
+
+		e.buf.WriteString(x.Text('g', -1))
+		return nil
+	}
+
+	if v.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(v.Type()).Implements(textMarshalerType) {
+		v = v.Addr()
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+
+		e.writeStr(string(text))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf.WriteString("true")
+		} else {
+			e.buf.WriteString("false")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		if f != nil && f.hasOpt("hex") {
+			if i < 0 {
+				e.buf.WriteString("-0x" + strconv.FormatUint(uint64(-i), 16))
+			} else {
+				e.buf.WriteString("0x" + strconv.FormatInt(i, 16))
+			}
+		} else {
+			e.buf.WriteString(strconv.FormatInt(i, 10))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if f != nil && f.hasOpt("hex") {
+			e.buf.WriteString("0x" + strconv.FormatUint(v.Uint(), 16))
+		} else {
+			e.buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+		}
+	case reflect.Float32:
+		return e.writeFloat(v, v.Float(), 32)
+	case reflect.Float64:
+		return e.writeFloat(v, v.Float(), 64)
+	case reflect.String:
+		e.writeStr(v.String())
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+
+		if v.Kind() == reflect.Ptr {
+			if err := e.enter(v); err != nil {
+				return err
+			}
+
+			defer e.leave(v)
+		}
+
+		return e.encode(v.Elem(), f)
+	case reflect.Slice:
+		if v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+
+		if v.Len() > 0 {
+			if err := e.enter(v); err != nil {
+				return err
+			}
+
+			defer e.leave(v)
+		}
+
+		return e.encodeArray(v)
+	case reflect.Array:
+		return e.encodeArray(v)
+	case reflect.Map:
+		if v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+
+		if err := e.enter(v); err != nil {
+			return err
+		}
+
+		defer e.leave(v)
+
+		return e.encodeMap(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	default:
+		return &UnsupportedValueError{Value: v, Msg: "type " + v.Type().String()}
+	}
+
+	return nil
+}
+
+func (e *encodeState) encodeArray(v reflect.Value) error {
+	return e.writeList('[', ']', v.Len(), func(i int) error {
+		return e.encode(v.Index(i), nil)
+	})
+}
+
+func (e *encodeState) encodeMap(v reflect.Value) error {
+	type entry struct {
+		key string
+		val reflect.Value
+	}
+
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKeyString(iter.Key())
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry{key: key, val: iter.Value()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	return e.writeList('{', '}', len(entries), func(i int) error {
+		e.writeKey(entries[i].key)
+		return e.encode(entries[i].val, nil)
+	})
+}
+
+// mapKeyString convert map key to object key
+func mapKeyString(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+
+	return "", &UnsupportedValueError{Value: k, Msg: "map key type " + k.Type().String()}
+}
+
+func (e *encodeState) encodeStruct(v reflect.Value) error {
+	type member struct {
+		f   *field
+		val reflect.Value
+	}
+
+	fields := typeFields(v.Type())
+	members := make([]member, 0, len(fields))
+	for i := range fields {
+		f := &fields[i]
+		fv, ok := fieldValue(v, f.index)
+		if !ok {
+			continue
+		}
+
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		members = append(members, member{f: f, val: fv})
+	}
+
+	return e.writeList('{', '}', len(members), func(i int) error {
+		m := members[i]
+		if comment := v.Type().FieldByIndex(m.f.index).Tag.Get("comment"); comment != "" {
+			e.writeComment(comment)
+		}
+
+		e.writeKey(m.f.name)
+		return e.encode(m.val, m.f)
+	})
+}
+
+// fieldValue return nested field, ok is false if an embedded struct pointer is nil
+func fieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+
+	return false
+}
+
+// encodeJSON5 write parsed value
+func (e *encodeState) encodeJSON5(json *JSON5) error {
+	switch json.kind {
+	case String:
+		e.writeStr(json.String())
+	case Integer, Float, Infinity, NaN:
+		return e.encode(reflect.ValueOf(json.Number()), nil)
+	case Boolean, Null:
+		e.buf.WriteString(string(json.raw))
+	case Array:
+		vals := json.Array()
+		return e.writeList('[', ']', len(vals), func(i int) error {
+			return e.encodeJSON5(vals[i])
+		})
+	case Object:
+		members := json.Members()
+		return e.writeList('{', '}', len(members), func(i int) error {
+			e.writeKey(members[i].Key)
+			return e.encodeJSON5(members[i].Value)
+		})
+	}
+
+	return nil
+}
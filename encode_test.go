@@ -0,0 +1,121 @@
+package json5extract
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestMarshalKeys(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"name", `{name:1}`},
+		{"$id_2", `{$id_2:1}`},
+		{"class", `{"class":1}`},
+		{"new", `{"new":1}`},
+		{"with space", `{"with space":1}`},
+		{"1st", `{"1st":1}`},
+	}
+
+	for _, test := range tests {
+		b, err := Marshal(map[string]int{test.key: 1}, &MarshalOptions{UnquotedKeys: true})
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", test.key, err)
+			continue
+		}
+
+		if string(b) != test.want {
+			t.Errorf("%q: got %s, want %s", test.key, b, test.want)
+		}
+	}
+}
+
+func TestMarshalNumbers(t *testing.T) {
+	big1 := new(big.Int)
+	big1.SetString("123456789012345678901234567890", 10)
+	bigF := big.NewFloat(1.5)
+
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{struct{ N Number }{}, `{"N":0}`},
+		{struct{ B big.Int }{*big1}, `{"B":123456789012345678901234567890}`},
+		{&struct{ B big.Int }{*big1}, `{"B":123456789012345678901234567890}`},
+		{struct{ F big.Float }{*bigF}, `{"F":1.5}`},
+		{&struct{ F big.Float }{*bigF}, `{"F":1.5}`},
+		{struct{ B *big.Int }{}, `{"B":null}`},
+		{[]big.Int{*big.NewInt(-7)}, `[-7]`},
+		{big1, `123456789012345678901234567890`},
+	}
+
+	for _, test := range tests {
+		b, err := Marshal(test.in)
+		if err != nil {
+			t.Errorf("%#v: unexpected error %v", test.in, err)
+			continue
+		}
+
+		if string(b) != test.want {
+			t.Errorf("%#v: got %s, want %s", test.in, b, test.want)
+		}
+	}
+
+	// Number read from the source keep its literal
+	json, err := Parse([]byte(`[0x1F, +.5, -0]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var nums []Number
+	if err := json.Decode(&nums); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err := Marshal(nums); err != nil || string(b) != `[0x1F,+.5,-0]` {
+		t.Errorf("got %s, %v, want [0x1F,+.5,-0]", b, err)
+	}
+}
+
+type chain struct {
+	Next *chain `json5:",omitempty"`
+}
+
+func TestMarshalCycles(t *testing.T) {
+	// deep chain without cycle
+	var deep *chain
+	for i := 0; i < 5000; i++ {
+		deep = &chain{Next: deep}
+	}
+
+	b, err := Marshal(deep)
+	if err != nil {
+		t.Fatalf("deep chain: unexpected error %v", err)
+	}
+
+	if want := strings.Repeat(`{"Next":`, 4999) + "{}" + strings.Repeat("}", 4999); string(b) != want {
+		t.Errorf("deep chain: got %.40s...", b)
+	}
+
+	// same value twice is not a cycle
+	shared := &chain{}
+	if b, err := Marshal([]*chain{shared, shared}); err != nil || string(b) != `[{},{}]` {
+		t.Errorf("shared pointer: got %s, %v", b, err)
+	}
+
+	loop := &chain{}
+	loop.Next = loop
+	m := map[string]interface{}{}
+	m["m"] = m
+	s := []interface{}{nil}
+	s[0] = s
+
+	for name, v := range map[string]interface{}{"pointer": loop, "map": m, "slice": s} {
+		_, err := Marshal(v)
+		if _, ok := err.(*UnsupportedValueError); !ok {
+			t.Errorf("%s: got %v, want UnsupportedValueError", name, err)
+		}
+	}
+}
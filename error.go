@@ -1,7 +1,136 @@
 package json5extract
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrInvalidFormat occured when a data is invalid format, such as unquoted string with hex escape (\x{hex}{hex}),
-// or invalid escape after reverse solidus (\{esc})
+// or invalid escape after reverse solidus (\{esc}). Every SyntaxError is ErrInvalidFormat
 var ErrInvalidFormat = errors.New(("Invalid format"))
+
+// ErrLimitExceeded occured when a value exceeds one of Limits. Every LimitError is ErrLimitExceeded
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// ErrNotFound occured when a path does not exist in a value. Every PointerError is ErrNotFound
+var ErrNotFound = errors.New("not found")
+
+// Syntax error codes
+const (
+	// CodeUnexpectedEOF is used when input end in the middle of a value
+	CodeUnexpectedEOF = iota + 1
+	// CodeUnexpectedChar is used when a char is not allowed at its position
+	CodeUnexpectedChar
+	// CodeUnterminatedString is used when a string has no closing quote or contains unescaped line terminator
+	CodeUnterminatedString
+	// CodeBadEscape is used when an escape sequence in string or identifier is invalid
+	CodeBadEscape
+	// CodeMissingColon is used when an object key is not followed by colon
+	CodeMissingColon
+	// CodeMissingComma is used when array elements or object members are not separated by comma
+	CodeMissingComma
+	// CodeInvalidKey is used when an object key is not a string nor a valid identifier
+	CodeInvalidKey
+	// CodeInvalidNumber is used when a number is malformed
+	CodeInvalidNumber
+	// CodeInvalidLiteral is used when true, false, null, Infinity or NaN is misspelled
+	CodeInvalidLiteral
+	// CodeInvalidComment is used when a comment is malformed
+	CodeInvalidComment
+	// CodeTrailingGarbage is used when a document has content after its value
+	CodeTrailingGarbage
+	// CodeNumberRange is used when a number overflow int64 or float64 while
+	// Options.RejectNumberOverflow is set
+	CodeNumberRange
+)
+
+var codeMessages = map[int]string{
+	CodeUnexpectedEOF:      "unexpected end of input",
+	CodeUnexpectedChar:     "unexpected character",
+	CodeUnterminatedString: "unterminated string",
+	CodeBadEscape:          "invalid escape sequence",
+	CodeMissingColon:       "missing colon after object key",
+	CodeMissingComma:       "missing comma",
+	CodeInvalidKey:         "invalid object key",
+	CodeInvalidNumber:      "invalid number",
+	CodeInvalidLiteral:     "invalid literal",
+	CodeInvalidComment:     "invalid comment",
+	CodeTrailingGarbage:    "trailing garbage after value",
+	CodeNumberRange:        "number out of range",
+}
+
+// EOFChar is SyntaxError.Char when error occured at the end of input
+const EOFChar = -1
+
+// SyntaxError describe invalid JSON5 syntax. errors.Is(err, ErrInvalidFormat) report true for SyntaxError
+type SyntaxError struct {
+	// Code is one of Code* constants
+	Code int
+	// Pos is position of the offending char
+	Pos Position
+	// Char is the offending char, EOFChar at the end of input
+	Char rune
+	// Expected describe what was expected instead of Char, may be empty
+	Expected string
+	// Msg is a human readable message
+	Msg string
+}
+
+func newSyntaxError(code int, pos Position, char rune, expected string) *SyntaxError {
+	msg := codeMessages[code]
+	if char == EOFChar {
+		if code != CodeUnexpectedEOF {
+			msg += " at end of input"
+		}
+	} else {
+		msg += fmt.Sprintf(" %q", char)
+	}
+
+	if expected != "" {
+		msg += ", expected " + expected
+	}
+
+	return &SyntaxError{Code: code, Pos: pos, Char: char, Expected: expected, Msg: msg}
+}
+
+func (err *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d (offset %d)", err.Msg, err.Pos.Line, err.Pos.Column, err.Pos.Offset)
+}
+
+// Is report whether target is ErrInvalidFormat
+func (err *SyntaxError) Is(target error) bool {
+	return target == ErrInvalidFormat
+}
+
+// DuplicateKeyError occured when a key repeats in an object while DuplicateReject policy is used
+type DuplicateKeyError struct {
+	Key string
+	// First is position of the first key
+	First Position
+	// Pos is position of the repeated key
+	Pos Position
+}
+
+func (err *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate key %q at line %d column %d, first defined at line %d column %d",
+		err.Key, err.Pos.Line, err.Pos.Column, err.First.Line, err.First.Column)
+}
+
+// LimitError occured when a value exceeds one of Limits, the value is skipped
+type LimitError struct {
+	// Limit is the name of exceeded Limits field
+	Limit string
+	// Max is the value of exceeded Limits field
+	Max int
+	// Pos is position where the limit was exceeded
+	Pos Position
+}
+
+func (err *LimitError) Error() string {
+	return fmt.Sprintf("%s %d exceeded at line %d, column %d (offset %d)", err.Limit, err.Max, err.Pos.Line, err.Pos.Column, err.Pos.Offset)
+}
+
+// Is report whether target is ErrLimitExceeded
+func (err *LimitError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
@@ -0,0 +1,177 @@
+package json5extract
+
+import (
+	"context"
+	"io"
+)
+
+// Extractor extract JSON5 values one by one from a source, values are returned as soon as
+// they are complete. Only input of the value currently being parsed is kept in memory
+type Extractor struct {
+	r   *reader
+	err error
+	// last rune before the next candidate
+	prev rune
+}
+
+// NewExtractor create Extractor reading from rdr. Only the first non nil opts is used
+func NewExtractor(rdr io.Reader, opts ...*Options) *Extractor {
+	return NewExtractorContext(context.Background(), rdr, opts...)
+}
+
+// NewExtractorContext create Extractor reading from rdr. Next return ctx.Err() once ctx is done.
+// Only the first non nil opts is used
+func NewExtractorContext(ctx context.Context, rdr io.Reader, opts ...*Options) *Extractor {
+	r := newReader(rdr)
+	r.ctx = ctx
+	r.opts = optionsOf(opts)
+
+	return &Extractor{r: r}
+}
+
+// Next return the next extracted value. Return io.EOF when there is no more value
+func (ext *Extractor) Next() (*JSON5, error) {
+	if ext.err != nil {
+		return nil, ext.err
+	}
+
+	// context is otherwise checked only periodically while reading
+	if err := ext.r.checkContext(); err != nil {
+		ext.err = err
+		return nil, err
+	}
+
+	json5, err := ext.next()
+	if err != nil {
+		ext.err = err
+		return nil, err
+	}
+
+	return json5, nil
+}
+
+func (ext *Extractor) next() (*JSON5, error) {
+	r := ext.r
+	for {
+		// nothing before a new candidate is needed anymore
+		r.release()
+		char, _, err := r.ReadRune()
+		if err != nil {
+			return nil, err
+		}
+
+		prev := ext.prev
+		ext.prev = char
+		start := r.lastPos()
+		next := r.pos()
+
+		// scalar inside a word. A decimal point after an identifier char, like in
+		// v1.2.3, is part of the word too
+		if r.opts.WordBoundary && isCharScalarBegin(char) && isCharIDValid(prev, false) {
+			if char == '.' {
+				ext.prev = prev
+			}
+
+			continue
+		}
+
+		json5, err := parse(r, char)
+		if err != nil {
+			if err := r.err(); err != nil {
+				return nil, err
+			}
+
+			if r.opts.Diagnostics != nil {
+				r.opts.Diagnostics(Diagnostic{
+					Start:   start,
+					Kind:    kindOfChar(char),
+					Reached: r.pos(),
+					Err:     err,
+				})
+			}
+
+			// resume right after the beginning of the failed candidate, so valid
+			// values nested inside it can still be found
+			r.seek(next)
+			continue
+		}
+
+		if json5 == nil {
+			continue
+		}
+
+		if r.opts.WordBoundary && isScalar(json5) && !r.atWordBoundary() {
+			r.seek(next)
+			continue
+		}
+
+		if !r.opts.accept(json5) {
+			// look for acceptable values inside rejected array or object
+			if json5.kind == Array || json5.kind == Object {
+				r.seek(next)
+				continue
+			}
+
+			ext.prev = json5.raw[len(json5.raw)-1]
+			continue
+		}
+
+		ext.prev = json5.raw[len(json5.raw)-1]
+		setSource(json5, r.slice(json5.start, json5.end))
+		attachComments(json5, r.comments)
+
+		return json5, nil
+	}
+}
+
+// check if char may begin a scalar other than string
+func isCharScalarBegin(char rune) bool {
+	return isCharNumBegin(char) || char == 't' || char == 'f' || char == 'n'
+}
+
+// check if json is a scalar other than string
+func isScalar(json *JSON5) bool {
+	switch json.kind {
+	case Integer, Float, Infinity, NaN, Boolean, Null:
+		return true
+	}
+
+	return false
+}
+
+// Diagnostic describe a candidate abandoned during extraction because it could not be parsed
+type Diagnostic struct {
+	// Start is position of the first char of the candidate
+	Start Position
+	// Kind is the kind the candidate looked like from its first char. Numbers are
+	// reported as Integer, unless they begin with decimal point
+	Kind int
+	// Reached is position where parsing stopped
+	Reached Position
+	// Err is the reason the candidate was rejected
+	Err error
+}
+
+// kindOfChar return the kind of value beginning with char
+func kindOfChar(char rune) int {
+	switch char {
+	case '"', '\'':
+		return String
+	case 't', 'f':
+		return Boolean
+	case 'n':
+		return Null
+	case 'I':
+		return Infinity
+	case 'N':
+		return NaN
+	case '.':
+		return Float
+	case '[':
+		return Array
+	case '{':
+		return Object
+	}
+
+	return Integer
+}
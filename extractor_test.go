@@ -0,0 +1,235 @@
+package json5extract
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// compact return compact form of every value
+func compact(vals []*JSON5) []string {
+	strs := make([]string, len(vals))
+	for i, v := range vals {
+		strs[i] = string(v.Compact())
+	}
+
+	return strs
+}
+
+func equalStrs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestExtractRewind(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{`see [1, {"a":1} and more`, []string{`1`, `{"a":1}`}},
+		{`{"broken": [1, 2 {"ok":true}`, []string{`"broken"`, `1`, `2`, `{"ok":true}`}},
+		{`[[[ [1,2] `, []string{`[1,2]`}},
+		{`{"a": {"b": [3]} oops`, []string{`"a"`, `{"b":[3]}`}},
+		{`"unterminated {"x":1}`, []string{`"unterminated {"`, `1`}},
+	}
+
+	for _, test := range tests {
+		vals, err := FromString(test.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", test.in, err)
+			continue
+		}
+
+		if got := compact(vals); !equalStrs(got, test.want) {
+			t.Errorf("%q: got %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestExtractDeepUnterminated(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{strings.Repeat("[", 20000), 0},
+		{strings.Repeat(`{"a":`, 5000), 5000},
+		{strings.Repeat(`[{"a":[`, 5000) + "1", 5001},
+	}
+
+	for _, test := range tests {
+		done := make(chan struct{})
+		var vals []*JSON5
+		var err error
+		go func() {
+			vals, err = FromString(test.in)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%.20q...: extraction did not finish in time", test.in)
+		}
+
+		if err != nil {
+			t.Errorf("%.20q...: unexpected error %v", test.in, err)
+		}
+
+		if len(vals) != test.want {
+			t.Errorf("%.20q...: got %d values, want %d", test.in, len(vals), test.want)
+		}
+	}
+}
+
+func TestExtractorStreaming(t *testing.T) {
+	pr, pw := io.Pipe()
+	ext := NewExtractor(pr)
+
+	// every value must be returned before the rest of the input is written
+	chunks := []struct {
+		in   string
+		want string
+	}{
+		{`log: {"id": 1} `, `{"id":1}`},
+		{`then [true, null] `, `[true,null]`},
+		{`and 'str' `, `'str'`},
+	}
+
+	for _, c := range chunks {
+		go pw.Write([]byte(c.in))
+
+		val, err := ext.Next()
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", c.in, err)
+		}
+
+		if got := string(val.Compact()); got != c.want {
+			t.Fatalf("%q: got %q, want %q", c.in, got, c.want)
+		}
+	}
+
+	pw.Close()
+	if _, err := ext.Next(); err != io.EOF {
+		t.Fatalf("got %v at the end, want io.EOF", err)
+	}
+
+	// error is sticky
+	if _, err := ext.Next(); err != io.EOF {
+		t.Fatalf("got %v after the end, want io.EOF", err)
+	}
+}
+
+func TestExtractorMatchesFromReader(t *testing.T) {
+	in := `a 1 b {"x": [2, 3]} c "d" [4, {"e": null}] 5.5`
+	want, err := FromString(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ext := NewExtractor(strings.NewReader(in))
+	var got []*JSON5
+	for {
+		val, err := ext.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got = append(got, val)
+	}
+
+	if !equalStrs(compact(got), compact(want)) {
+		t.Errorf("got %q, want %q", compact(got), compact(want))
+	}
+}
+
+func TestExtractContextDone(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		ctx  context.Context
+		want error
+	}{
+		{cancelled, context.Canceled},
+		{expired, context.DeadlineExceeded},
+	}
+
+	for _, test := range tests {
+		vals, err := FromStringContext(test.ctx, "1 2 3")
+		if err != test.want || len(vals) != 0 {
+			t.Errorf("got %d values and %v, want no value and %v", len(vals), err, test.want)
+		}
+	}
+}
+
+func TestExtractorCancelBetweenValues(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ext := NewExtractorContext(ctx, strings.NewReader("1 2 3"))
+	if _, err := ext.Next(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	cancel()
+	if _, err := ext.Next(); err != context.Canceled {
+		t.Fatalf("got %v after cancel, want context.Canceled", err)
+	}
+}
+
+func TestExtractReservedWordKeys(t *testing.T) {
+	// any IdentifierName is a valid key, reserved words included
+	for _, word := range rsvWords {
+		in := "{" + string(word) + ": 1}"
+		vals, err := FromString(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := compact(vals); !equalStrs(got, []string{"{" + string(word) + ":1}"}) {
+			t.Errorf("%s: got %q", in, got)
+		}
+	}
+}
+
+func TestExtractWordBoundary(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{`id123 true1 nulls 42`, []string{`42`}},
+		{`x=1, (true) [null]`, []string{`1`, `true`, `[null]`}},
+		{`v1.2.3`, []string{}},
+		{`pi is 3.14.`, []string{}},
+		{`a.5 but .5`, []string{`.5`}},
+		{`1.5 and 2.`, []string{`1.5`, `2.`}},
+		{`"str"abc`, []string{`"str"`}},
+	}
+
+	for _, test := range tests {
+		vals, err := FromString(test.in, &Options{WordBoundary: true})
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", test.in, err)
+			continue
+		}
+
+		if got := compact(vals); !equalStrs(got, test.want) {
+			t.Errorf("%q: got %q, want %q", test.in, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package json5extract
+
+import (
+	"reflect"
+	"strings"
+)
+
+// field is an exported struct field, promoted fields of embedded structs included
+type field struct {
+	name string
+	// index sequence for reflect.Value.FieldByIndex
+	index     []int
+	typ       reflect.Type
+	tagged    bool
+	omitEmpty bool
+	// tag options other than omitempty, such as hex
+	opts []string
+}
+
+func (f *field) hasOpt(opt string) bool {
+	for _, o := range f.opts {
+		if o == opt {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTag return name and options of json5 tag, falling back to json tag
+func parseTag(sf reflect.StructField) (name string, opts []string, ok bool) {
+	tag, ok := sf.Tag.Lookup("json5")
+	if !ok {
+		tag, ok = sf.Tag.Lookup("json")
+	}
+
+	if !ok {
+		return "", nil, false
+	}
+
+	parts := strings.Split(tag, ",")
+
+	return parts[0], parts[1:], true
+}
+
+// typeFields return fields of struct type t. Fields of embedded structs without name tag are
+// promoted, a field hide promoted fields of the same name from deeper embedded structs
+func typeFields(t reflect.Type) []field {
+	var fields []field
+	collectFields(t, nil, &fields, map[reflect.Type]bool{})
+
+	// keep the shallowest field of every name, tagged one first
+	best := make(map[string]int)
+	for i, f := range fields {
+		j, ok := best[f.name]
+		if !ok {
+			best[f.name] = i
+			continue
+		}
+
+		old := fields[j]
+		if len(f.index) < len(old.index) || (len(f.index) == len(old.index) && f.tagged && !old.tagged) {
+			best[f.name] = i
+		}
+	}
+
+	result := make([]field, 0, len(best))
+	for i, f := range fields {
+		if best[f.name] == i {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
+
+func collectFields(t reflect.Type, index []int, fields *[]field, visited map[reflect.Type]bool) {
+	if visited[t] {
+		return
+	}
+
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, opts, tagged := parseTag(sf)
+		if name == "-" && len(opts) == 0 {
+			continue
+		}
+
+		idx := make([]int, len(index)+1)
+		copy(idx, index)
+		idx[len(index)] = i
+
+		ft := sf.Type
+		if sf.Anonymous && name == "" {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				// pointer to unexported struct can not be allocated
+				if sf.PkgPath != "" && sf.Type.Kind() == reflect.Ptr {
+					continue
+				}
+
+				collectFields(ft, idx, fields, visited)
+				continue
+			}
+		}
+
+		// unexported
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		f := field{name: name, index: idx, typ: sf.Type, tagged: tagged}
+		for _, o := range opts {
+			if o == "omitempty" {
+				f.omitEmpty = true
+				continue
+			}
+
+			f.opts = append(f.opts, o)
+		}
+
+		*fields = append(*fields, f)
+	}
+}
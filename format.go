@@ -0,0 +1,306 @@
+package json5extract
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// Quote styles of FormatOptions
+const (
+	// QuoteDouble write strings with double quotes
+	QuoteDouble = iota
+	// QuoteSingle write strings with single quotes
+	QuoteSingle
+	// QuotePreserve write strings with the quote used in the source
+	QuotePreserve
+)
+
+// Key quoting policies of FormatOptions
+const (
+	// KeyQuoteAlways quote every key
+	KeyQuoteAlways = iota
+	// KeyQuoteAsNeeded leave keys which are valid identifiers unquoted
+	KeyQuoteAsNeeded
+	// KeyQuotePreserve quote keys which are quoted in the source
+	KeyQuotePreserve
+)
+
+// Trailing comma policies of FormatOptions
+const (
+	// TrailingCommaNone never write comma after the last element
+	TrailingCommaNone = iota
+	// TrailingCommaMultiline write comma after the last element of arrays and objects
+	// written on multiple lines
+	TrailingCommaMultiline
+)
+
+// FormatOptions control text written by JSON5.Format. The zero value indent with 2 spaces,
+// use double quotes, quote every key and write no trailing comma
+type FormatOptions struct {
+	// IndentWidth is number of spaces of one nesting level, 0 means 2
+	IndentWidth int
+	// UseTabs indent with one tab per nesting level instead of spaces
+	UseTabs bool
+	// Quote is quote style of strings and quoted keys
+	Quote int
+	// KeyQuote is key quoting policy
+	KeyQuote int
+	// TrailingComma is trailing comma policy
+	TrailingComma int
+	// MaxWidth is maximum line width, in runes, of an array written on a single line.
+	// Arrays not fitting are written one element per line. 0 means every non empty array
+	// is written on multiple lines
+	MaxWidth int
+	// Minify write everything on a single line without whitespace, ignoring indentation,
+	// trailing comma and width options
+	Minify bool
+}
+
+type formatState struct {
+	buf    bytes.Buffer
+	opts   *FormatOptions
+	indent string
+	depth  int
+	// rune width of values written on a single line, computed once by flatWidth
+	widths map[*JSON5]int
+}
+
+func newFormatState(opts *FormatOptions) *formatState {
+	f := &formatState{opts: opts}
+	switch {
+	case opts.UseTabs:
+		f.indent = "\t"
+	case opts.IndentWidth > 0:
+		f.indent = string(bytes.Repeat([]byte{' '}, opts.IndentWidth))
+	default:
+		f.indent = "  "
+	}
+
+	return f
+}
+
+// Format write the value as JSON5 text formatted by opts. Only the first non nil opts is used.
+// Numbers, booleans and null are written as in the source, strings are re-escaped
+func (json *JSON5) Format(opts ...*FormatOptions) []byte {
+	o := new(FormatOptions)
+	for _, opt := range opts {
+		if opt != nil {
+			o = opt
+			break
+		}
+	}
+
+	f := newFormatState(o)
+	f.format(json)
+
+	return f.buf.Bytes()
+}
+
+// newline write line break and indentation of current depth
+func (f *formatState) newline() {
+	f.buf.WriteByte('\n')
+	for i := 0; i < f.depth; i++ {
+		f.buf.WriteString(f.indent)
+	}
+}
+
+// column return rune width of the current line
+func (f *formatState) column() int {
+	line := f.buf.Bytes()
+	if i := bytes.LastIndexByte(line, '\n'); i >= 0 {
+		line = line[i+1:]
+	}
+
+	return utf8.RuneCount(line)
+}
+
+// quoteOf return quote of a string node by quote style, str may be nil
+func (f *formatState) quoteOf(str *JSON5) byte {
+	switch f.opts.Quote {
+	case QuoteSingle:
+		return '\''
+	case QuotePreserve:
+		if str != nil && len(str.raw) > 0 && str.raw[0] == '\'' {
+			return '\''
+		}
+	}
+
+	return '"'
+}
+
+func (f *formatState) writeKey(key *JSON5, name string) {
+	quoted := true
+	switch f.opts.KeyQuote {
+	case KeyQuoteAsNeeded:
+		quoted = !isIdentifierName(name)
+	case KeyQuotePreserve:
+		quoted = key == nil || len(key.raw) == 0 || key.raw[0] == '"' || key.raw[0] == '\'' || !isIdentifierName(name)
+	}
+
+	if quoted {
+		writeQuoted(&f.buf, name, f.quoteOf(key))
+	} else {
+		f.buf.WriteString(name)
+	}
+
+	f.buf.WriteByte(':')
+	if !f.opts.Minify {
+		f.buf.WriteByte(' ')
+	}
+}
+
+func (f *formatState) format(json *JSON5) {
+	switch json.kind {
+	case String:
+		writeQuoted(&f.buf, json.String(), f.quoteOf(json))
+	case Array:
+		vals := json.Array()
+		if f.fitArray(json) {
+			return
+		}
+
+		f.writeList('[', ']', len(vals), func(i int) {
+			f.format(vals[i])
+		})
+	case Object:
+		members := json.Members()
+		f.writeList('{', '}', len(members), func(i int) {
+			f.writeKey(members[i].Value.key, members[i].Key)
+			f.format(members[i].Value)
+		})
+	default:
+		f.buf.WriteString(string(json.raw))
+	}
+}
+
+// fitArray write array on a single line if it fits in MaxWidth
+func (f *formatState) fitArray(json *JSON5) bool {
+	if f.opts.Minify || f.opts.MaxWidth <= 0 || len(json.Array()) == 0 {
+		return false
+	}
+
+	if f.column()+f.flatWidth(json) > f.opts.MaxWidth {
+		return false
+	}
+
+	f.writeLine(json)
+
+	return true
+}
+
+// flatWidth return rune width of the value written by writeLine. Width of a container is
+// computed from widths of its elements, so every value is measured only once
+func (f *formatState) flatWidth(json *JSON5) int {
+	if w, ok := f.widths[json]; ok {
+		return w
+	}
+
+	var w int
+	switch json.kind {
+	case Array:
+		vals := json.Array()
+		w = 2 + 2*(len(vals)-1)
+		if len(vals) == 0 {
+			w = 2
+		}
+
+		for _, val := range vals {
+			w += f.flatWidth(val)
+		}
+	case Object:
+		members := json.Members()
+		w = 2 + 2*(len(members)-1)
+		if len(members) == 0 {
+			w = 2
+		}
+
+		for _, m := range members {
+			key := &formatState{opts: f.opts}
+			key.writeKey(m.Value.key, m.Key)
+			w += utf8.RuneCount(key.buf.Bytes()) + f.flatWidth(m.Value)
+		}
+	default:
+		line := &formatState{opts: f.opts}
+		line.format(json)
+		w = utf8.RuneCount(line.buf.Bytes())
+	}
+
+	if f.widths == nil {
+		f.widths = make(map[*JSON5]int)
+	}
+
+	f.widths[json] = w
+
+	return w
+}
+
+// writeLine write the value on a single line, with space after commas and colons
+func (f *formatState) writeLine(json *JSON5) {
+	switch json.kind {
+	case Array:
+		f.buf.WriteByte('[')
+		for i, val := range json.Array() {
+			if i > 0 {
+				f.buf.WriteString(", ")
+			}
+
+			f.writeLine(val)
+		}
+
+		f.buf.WriteByte(']')
+	case Object:
+		f.buf.WriteByte('{')
+		for i, m := range json.Members() {
+			if i > 0 {
+				f.buf.WriteString(", ")
+			}
+
+			f.writeKey(m.Value.key, m.Key)
+			f.writeLine(m.Value)
+		}
+
+		f.buf.WriteByte('}')
+	default:
+		f.format(json)
+	}
+}
+
+// writeList write n elements between open and close, write is called for every element
+func (f *formatState) writeList(open, close byte, n int, write func(i int)) {
+	f.buf.WriteByte(open)
+	if n == 0 {
+		f.buf.WriteByte(close)
+		return
+	}
+
+	if f.opts.Minify {
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				f.buf.WriteByte(',')
+			}
+
+			write(i)
+		}
+
+		f.buf.WriteByte(close)
+		return
+	}
+
+	f.depth++
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			f.buf.WriteByte(',')
+		}
+
+		f.newline()
+		write(i)
+	}
+
+	if f.opts.TrailingComma == TrailingCommaMultiline {
+		f.buf.WriteByte(',')
+	}
+
+	f.depth--
+	f.newline()
+	f.buf.WriteByte(close)
+}
@@ -0,0 +1,25 @@
+package json5extract
+
+import "testing"
+
+func TestFormatMaxWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		max  int
+		want string
+	}{
+		{`[1, 2, 3]`, 9, `[1, 2, 3]`},
+		{`[1, 2, 3]`, 8, "[\n  1,\n  2,\n  3\n]"},
+		{`{a: [1, 'é'], b: [1, 2, 3, 4]}`, 15, "{\n  \"a\": [1, \"é\"],\n  \"b\": [\n    1,\n    2,\n    3,\n    4\n  ]\n}"},
+		{`[[1, {a: []}], [2]]`, 21, `[[1, {"a": []}], [2]]`},
+		{`[[1, {a: []}], [2]]`, 20, "[\n  [1, {\"a\": []}],\n  [2]\n]"},
+		{`[[]]`, 0, "[\n  []\n]"},
+	}
+
+	for _, test := range tests {
+		json := mustParse(t, []byte(test.in))
+		if got := string(json.Format(&FormatOptions{MaxWidth: test.max})); got != test.want {
+			t.Errorf("%s (%d): got\n%s\nwant\n%s", test.in, test.max, got, test.want)
+		}
+	}
+}
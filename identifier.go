@@ -1,15 +1,12 @@
 package json5extract
 
-import (
-	"io"
-	"unicode"
-)
+import "unicode"
 
 // This file contains parser method for unquoted string object identifier. This string can't be used as value, only
 // can be used as object identifier
 
-// This is list of reserved words that can't be used as unquoted object identifier name,
-// also include future reserved words, and a list of considered future reserved words
+// This is list of reserved words, also include future reserved words, and a list of considered future
+// reserved words. JSON5 accept them as unquoted object identifier name, Marshal quote them
 // References:
 // Reserved words
 // https://www.ecma-international.org/ecma-262/5.1/#sec-7.6.1.1
@@ -36,27 +33,28 @@ var rsvWords = [][]rune{
 }
 
 // Identifier name obey the  ECMAScript 5.1 Lexical Grammar, see
-// https://www.ecma-international.org/ecma-262/5.1/#sec-7.6 "Identifier Names and Identifiers"
-func parseIdentifier(r reader, char rune) (id, raw []rune, err error) {
+// https://www.ecma-international.org/ecma-262/5.1/#sec-7.6 "Identifier Names and Identifiers".
+// The key is returned as String node positioned at the key name, the key terminator is consumed
+func parseIdentifier(r *reader, char rune) (*JSON5, error) {
 	rs := make([]rune, 0)
+	start := r.lastPos()
 
 	// double quoted string
 	if char == '"' {
 		// find key
 		str, err := parseStr(r, doubleQuotedStr)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
+		str.start = start
+		str.end = r.pos()
+
 		// find key terminator
 		for {
 			char, _, err := r.ReadRune()
 			if err != nil {
-				if err == io.EOF {
-					return nil, nil, ErrInvalidFormat
-				}
-
-				return nil, nil, err
+				return nil, r.readErr(err, "':'")
 			}
 
 			if unicode.IsControl(char) || char == ' ' {
@@ -65,20 +63,20 @@ func parseIdentifier(r reader, char rune) (id, raw []rune, err error) {
 
 			if char == '/' {
 				if _, err := parseComment(r); err != nil {
-					return nil, nil, ErrInvalidFormat
+					return nil, err
 				}
 
 				continue
 			}
 
 			if char != ':' {
-				return nil, nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeMissingColon, "':'")
 			}
 
 			break
 		}
 
-		return []rune(str.val.(string)), str.raw, nil
+		return str, nil
 	}
 
 	// single quoted string
@@ -86,18 +84,17 @@ func parseIdentifier(r reader, char rune) (id, raw []rune, err error) {
 		// find key
 		str, err := parseStr(r, singleQuotedStr)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
+		str.start = start
+		str.end = r.pos()
+
 		// find key terminator
 		for {
 			char, _, err := r.ReadRune()
 			if err != nil {
-				if err == io.EOF {
-					return nil, nil, ErrInvalidFormat
-				}
-
-				return nil, nil, err
+				return nil, r.readErr(err, "':'")
 			}
 
 			if unicode.IsControl(char) || char == ' ' {
@@ -107,27 +104,27 @@ func parseIdentifier(r reader, char rune) (id, raw []rune, err error) {
 			// comment
 			if char == '/' {
 				if _, err := parseComment(r); err != nil {
-					return nil, nil, ErrInvalidFormat
+					return nil, err
 				}
 
 				continue
 			}
 
 			if char != ':' {
-				return nil, nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeMissingColon, "':'")
 			}
 
 			break
 		}
 
-		return []rune(str.val.(string)), str.raw, nil
+		return str, nil
 	}
 
 	// unicode
 	if char == '\\' {
 		rn, err := parseUnicode(r)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		char = rn
@@ -135,16 +132,13 @@ func parseIdentifier(r reader, char rune) (id, raw []rune, err error) {
 
 	if isCharIDValid(char, true) {
 		rs = append(rs, char)
+		end := r.pos()
 		// extract key name
 		isIDEnd := false
 		for {
 			char, _, err := r.ReadRune()
 			if err != nil {
-				if err == io.EOF {
-					return nil, nil, ErrInvalidFormat
-				}
-
-				return nil, nil, err
+				return nil, r.readErr(err, "':'")
 			}
 
 			// find terminator
@@ -155,7 +149,7 @@ func parseIdentifier(r reader, char rune) (id, raw []rune, err error) {
 				}
 
 				if char != ':' {
-					return nil, nil, ErrInvalidFormat
+					return nil, r.syntaxErr(CodeMissingColon, "':'")
 				}
 
 				break
@@ -165,17 +159,18 @@ func parseIdentifier(r reader, char rune) (id, raw []rune, err error) {
 			if char == '\\' {
 				rn, err := parseUnicode(r)
 				if err != nil {
-					return nil, nil, err
+					return nil, err
 				}
 
 				rs = append(rs, rn)
+				end = r.pos()
 				continue
 			}
 
 			// comment
 			if char == '/' {
 				if _, err := parseComment(r); err != nil {
-					return nil, nil, ErrInvalidFormat
+					return nil, err
 				}
 
 				continue
@@ -193,20 +188,17 @@ func parseIdentifier(r reader, char rune) (id, raw []rune, err error) {
 
 			if isCharIDValid(char, false) {
 				rs = append(rs, char)
+				end = r.pos()
 				continue
 			}
 
-			return nil, nil, ErrInvalidFormat
-		}
-
-		if isReservedWord(rs) {
-			return nil, nil, ErrInvalidFormat
+			return nil, r.syntaxErr(CodeInvalidKey, "identifier char or ':'")
 		}
 
-		return rs, rs, nil
+		return &JSON5{kind: String, val: string(rs), raw: rs, start: start, end: end}, nil
 	}
 
-	return nil, nil, ErrInvalidFormat
+	return nil, newSyntaxError(CodeInvalidKey, start, char, "string or identifier")
 }
 
 func isCharIDValid(char rune, begin bool) bool {
@@ -232,3 +224,31 @@ func isCharIDValid(char rune, begin bool) bool {
 
 	return false
 }
+
+// isIdentifierName check if str is a valid unquoted object key
+func isIdentifierName(str string) bool {
+	rs := []rune(str)
+	if len(rs) == 0 || !isCharIDValid(rs[0], true) {
+		return false
+	}
+
+	for _, c := range rs[1:] {
+		if !isCharIDValid(c, false) {
+			return false
+		}
+	}
+
+	return !isReservedName(str)
+}
+
+// isReservedName check if str is one of reserved words. Reserved words are valid keys, but they
+// are quoted when writing keys so every ECMAScript version accept them
+func isReservedName(str string) bool {
+	for _, word := range rsvWords {
+		if string(word) == str {
+			return true
+		}
+	}
+
+	return false
+}
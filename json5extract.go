@@ -1,43 +1,60 @@
 package json5extract
 
 import (
+	"bytes"
+	"context"
 	"io"
 	"os"
+	"strings"
 )
 
-// FromFile extract JSON5 strings from a file in path
-func FromFile(path string) ([]*JSON5, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
+// FromFile extract JSON5 strings from a file in path. Only the first non nil opts is used
+func FromFile(path string, opts ...*Options) ([]*JSON5, error) {
+	return FromFileContext(context.Background(), path, opts...)
+}
 
-	reader, err := readFromReader(f)
-	if err != nil {
-		return nil, err
-	}
+// FromBytes extract JSON5 strings from array of bytes. Only the first non nil opts is used
+func FromBytes(byts []byte, opts ...*Options) ([]*JSON5, error) {
+	return FromBytesContext(context.Background(), byts, opts...)
+}
 
-	return parseAll(reader)
+// FromReader extract JSON5 strings from io.Reader. Only the first non nil opts is used
+func FromReader(rdr io.Reader, opts ...*Options) ([]*JSON5, error) {
+	return FromReaderContext(context.Background(), rdr, opts...)
 }
 
-// FromBytes extract JSON5 strings from array of bytes
-func FromBytes(byts []byte) ([]*JSON5, error) {
-	r := readFromBytes(byts)
-	return parseAll(r)
+// FromString extract JSON5 strings from string. Only the first non nil opts is used
+func FromString(str string, opts ...*Options) ([]*JSON5, error) {
+	return FromStringContext(context.Background(), str, opts...)
 }
 
-// FromReader extract JSON5 strings from io.Reader
-func FromReader(rdr io.Reader) ([]*JSON5, error) {
-	r, err := readFromReader(rdr)
+// FromFileContext extract JSON5 strings from a file in path. If ctx is done before
+// extraction finish, values extracted so far are returned along with ctx.Err()
+func FromFileContext(ctx context.Context, path string, opts ...*Options) ([]*JSON5, error) {
+	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return parseAll(r)
+	defer f.Close()
+
+	return parseAll(NewExtractorContext(ctx, f, opts...))
+}
+
+// FromBytesContext extract JSON5 strings from array of bytes. If ctx is done before
+// extraction finish, values extracted so far are returned along with ctx.Err()
+func FromBytesContext(ctx context.Context, byts []byte, opts ...*Options) ([]*JSON5, error) {
+	return parseAll(NewExtractorContext(ctx, bytes.NewReader(byts), opts...))
+}
+
+// FromReaderContext extract JSON5 strings from io.Reader. If ctx is done before
+// extraction finish, values extracted so far are returned along with ctx.Err()
+func FromReaderContext(ctx context.Context, rdr io.Reader, opts ...*Options) ([]*JSON5, error) {
+	return parseAll(NewExtractorContext(ctx, rdr, opts...))
 }
 
-// FromString extract JSON5 strings from string
-func FromString(str string) ([]*JSON5, error) {
-	r := readFromString(str)
-	return parseAll(r)
+// FromStringContext extract JSON5 strings from string. If ctx is done before
+// extraction finish, values extracted so far are returned along with ctx.Err()
+func FromStringContext(ctx context.Context, str string, opts ...*Options) ([]*JSON5, error) {
+	return parseAll(NewExtractorContext(ctx, strings.NewReader(str), opts...))
 }
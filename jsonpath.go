@@ -0,0 +1,707 @@
+package json5extract
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Node is a value selected by a JSONPath query
+type Node struct {
+	// Path locate Value from the queried value
+	Path  Path
+	Value *JSON5
+}
+
+// JSONPath is a compiled JSONPath query (RFC 9535), safe for concurrent use
+type JSONPath struct {
+	query string
+	root  *pathQuery
+}
+
+// QueryError occured when a JSONPath query is malformed
+type QueryError struct {
+	Query string
+	// Offset is rune offset of the offending char in Query
+	Offset int
+	Msg    string
+}
+
+func (err *QueryError) Error() string {
+	return fmt.Sprintf("invalid JSONPath %q: %s at offset %d", err.Query, err.Msg, err.Offset)
+}
+
+// CompileJSONPath compile JSONPath query, such as $.items[?@.price > 10].id.
+//
+// Child (.name, ['name'], [0], [*]) and descendant (..name, ..[*]) segments, wildcards, negative indexes,
+// slices ([start:end:step]), unions ([0, 'a']) and filters are supported. Filters support comparisons,
+// existence tests, &&, ||, ! and the functions length, count, match, search and value. String and number
+// literals follow RFC 9535, which allow JSON escapes and JSON numbers only
+func CompileJSONPath(query string) (*JSONPath, error) {
+	p := &queryParser{src: []rune(query), query: query}
+	if p.peek() != '$' {
+		return nil, p.errorf("query must begin with '$'")
+	}
+
+	root, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.i < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+
+	return &JSONPath{query: query, root: root}, nil
+}
+
+// String return the query
+func (path *JSONPath) String() string {
+	return path.query
+}
+
+// Select return nodes selected from json, in document order of selection
+func (path *JSONPath) Select(json *JSON5) []Node {
+	return path.root.eval(json, json)
+}
+
+// Query compile JSONPath query and select nodes from the value, see CompileJSONPath
+func (json *JSON5) Query(query string) ([]Node, error) {
+	path, err := CompileJSONPath(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return path.Select(json), nil
+}
+
+// Query extract every JSON5 value from rdr and select nodes matching JSONPath query from each of them.
+// Node paths are relative to the extracted value, which is the root of Node.Value parents. Nodes selected
+// before an error are returned with the error
+func Query(rdr io.Reader, query string, opts ...*Options) ([]Node, error) {
+	path, err := CompileJSONPath(query)
+	if err != nil {
+		return nil, err
+	}
+
+	json5s, err := parseAll(NewExtractor(rdr, opts...))
+	nodes := make([]Node, 0)
+	for _, json := range json5s {
+		nodes = append(nodes, path.Select(json)...)
+	}
+
+	return nodes, err
+}
+
+// Normalized return path as JSONPath normalized path (RFC 9535), such as $['a'][3]
+func (p Path) Normalized() string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, seg := range p {
+		b.WriteByte('[')
+		if seg.IsIndex() {
+			b.WriteString(strconv.Itoa(seg.Index))
+		} else {
+			b.WriteByte('\'')
+			for _, c := range seg.Key {
+				switch c {
+				case '\'', '\\':
+					b.WriteByte('\\')
+					b.WriteRune(c)
+				case '\b':
+					b.WriteString(`\b`)
+				case '\f':
+					b.WriteString(`\f`)
+				case '\n':
+					b.WriteString(`\n`)
+				case '\r':
+					b.WriteString(`\r`)
+				case '\t':
+					b.WriteString(`\t`)
+				default:
+					if c < 0x20 {
+						fmt.Fprintf(&b, `\u%04x`, c)
+						continue
+					}
+
+					b.WriteRune(c)
+				}
+			}
+
+			b.WriteByte('\'')
+		}
+
+		b.WriteByte(']')
+	}
+
+	return b.String()
+}
+
+// pathQuery is a query beginning with $ or, inside filters, with @
+type pathQuery struct {
+	relative bool
+	segs     []pathSegment
+}
+
+type pathSegment struct {
+	descendant bool
+	sels       []selector
+}
+
+// Selector kinds
+const (
+	selName = iota
+	selWildcard
+	selIndex
+	selSlice
+	selFilter
+)
+
+type selector struct {
+	kind  int
+	name  string
+	index int
+	// slice start, end and step, nil if omitted
+	slice  [3]*int
+	filter logicalExpr
+}
+
+// singular check if query select at most one node, having only name and index selectors
+func (q *pathQuery) singular() bool {
+	for _, seg := range q.segs {
+		if seg.descendant || len(seg.sels) != 1 {
+			return false
+		}
+
+		if kind := seg.sels[0].kind; kind != selName && kind != selIndex {
+			return false
+		}
+	}
+
+	return true
+}
+
+// eval return nodes selected from root, or from cur if query is relative
+func (q *pathQuery) eval(root, cur *JSON5) []Node {
+	start := root
+	if q.relative {
+		start = cur
+	}
+
+	nodes := []Node{{Value: start}}
+	for i := range q.segs {
+		nodes = q.segs[i].apply(root, nodes)
+	}
+
+	return nodes
+}
+
+func (seg *pathSegment) apply(root *JSON5, in []Node) []Node {
+	out := make([]Node, 0)
+	for _, n := range in {
+		if !seg.descendant {
+			out = seg.selectFrom(root, n, out)
+			continue
+		}
+
+		descend(n, func(d Node) {
+			out = seg.selectFrom(root, d, out)
+		})
+	}
+
+	return out
+}
+
+// descend call visit for n and each of its descendants, parents before children
+func descend(n Node, visit func(Node)) {
+	visit(n)
+	for _, c := range children(n) {
+		descend(c, visit)
+	}
+}
+
+// children return array elements or object member values of n
+func children(n Node) []Node {
+	switch n.Value.kind {
+	case Array:
+		vals := n.Value.Array()
+		nodes := make([]Node, len(vals))
+		for i, v := range vals {
+			nodes[i] = Node{Path: childPath(n.Path, Segment{Index: i}), Value: v}
+		}
+
+		return nodes
+	case Object:
+		members := n.Value.Members()
+		nodes := make([]Node, len(members))
+		for i, m := range members {
+			nodes[i] = Node{Path: childPath(n.Path, Segment{Key: m.Key, Index: -1}), Value: m.Value}
+		}
+
+		return nodes
+	}
+
+	return nil
+}
+
+func childPath(p Path, seg Segment) Path {
+	child := make(Path, len(p)+1)
+	copy(child, p)
+	child[len(p)] = seg
+
+	return child
+}
+
+func (seg *pathSegment) selectFrom(root *JSON5, n Node, out []Node) []Node {
+	for i := range seg.sels {
+		sel := &seg.sels[i]
+		switch sel.kind {
+		case selName:
+			if n.Value.kind != Object {
+				continue
+			}
+
+			if v, ok := n.Value.Get(sel.name); ok {
+				out = append(out, Node{Path: childPath(n.Path, Segment{Key: sel.name, Index: -1}), Value: v})
+			}
+		case selWildcard:
+			out = append(out, children(n)...)
+		case selIndex:
+			if n.Value.kind != Array {
+				continue
+			}
+
+			vals := n.Value.Array()
+			i := sel.index
+			if i < 0 {
+				i += len(vals)
+			}
+
+			if i >= 0 && i < len(vals) {
+				out = append(out, Node{Path: childPath(n.Path, Segment{Index: i}), Value: vals[i]})
+			}
+		case selSlice:
+			if n.Value.kind != Array {
+				continue
+			}
+
+			vals := n.Value.Array()
+			for _, i := range sliceIndexes(sel.slice, len(vals)) {
+				out = append(out, Node{Path: childPath(n.Path, Segment{Index: i}), Value: vals[i]})
+			}
+		case selFilter:
+			for _, c := range children(n) {
+				if sel.filter.test(root, c.Value) {
+					out = append(out, c)
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+// sliceIndexes return indexes selected by slice from array of length n
+func sliceIndexes(slice [3]*int, n int) []int {
+	step := 1
+	if slice[2] != nil {
+		step = *slice[2]
+	}
+
+	if step == 0 {
+		return nil
+	}
+
+	normalize := func(i int) int {
+		if i < 0 {
+			return n + i
+		}
+
+		return i
+	}
+
+	clamp := func(i, lo, hi int) int {
+		if i < lo {
+			return lo
+		}
+
+		if i > hi {
+			return hi
+		}
+
+		return i
+	}
+
+	var indexes []int
+	if step > 0 {
+		start, end := 0, n
+		if slice[0] != nil {
+			start = normalize(*slice[0])
+		}
+
+		if slice[1] != nil {
+			end = normalize(*slice[1])
+		}
+
+		for i := clamp(start, 0, n); i < clamp(end, 0, n); i += step {
+			indexes = append(indexes, i)
+		}
+
+		return indexes
+	}
+
+	start, end := n-1, -n-1
+	if slice[0] != nil {
+		start = normalize(*slice[0])
+	}
+
+	if slice[1] != nil {
+		end = normalize(*slice[1])
+	}
+
+	for i := clamp(start, -1, n-1); clamp(end, -1, n-1) < i; i += step {
+		indexes = append(indexes, i)
+	}
+
+	return indexes
+}
+
+// logicalExpr is a filter expression evaluated to true or false, cur is the value bound to @
+type logicalExpr interface {
+	test(root, cur *JSON5) bool
+}
+
+// valueExpr is a filter expression evaluated to a value, ok is false if it evaluate to nothing
+type valueExpr interface {
+	value(root, cur *JSON5) (val *JSON5, ok bool)
+}
+
+type orExpr []logicalExpr
+
+func (expr orExpr) test(root, cur *JSON5) bool {
+	for _, e := range expr {
+		if e.test(root, cur) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type andExpr []logicalExpr
+
+func (expr andExpr) test(root, cur *JSON5) bool {
+	for _, e := range expr {
+		if !e.test(root, cur) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type notExpr struct {
+	expr logicalExpr
+}
+
+func (expr notExpr) test(root, cur *JSON5) bool {
+	return !expr.expr.test(root, cur)
+}
+
+// existExpr test if query select any node
+type existExpr struct {
+	query *pathQuery
+}
+
+func (expr existExpr) test(root, cur *JSON5) bool {
+	return len(expr.query.eval(root, cur)) > 0
+}
+
+type literalExpr struct {
+	val *JSON5
+}
+
+func (expr literalExpr) value(root, cur *JSON5) (*JSON5, bool) {
+	return expr.val, true
+}
+
+// singularExpr is value of a singular query
+type singularExpr struct {
+	query *pathQuery
+}
+
+func (expr singularExpr) value(root, cur *JSON5) (*JSON5, bool) {
+	nodes := expr.query.eval(root, cur)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+
+	return nodes[0].Value, true
+}
+
+type cmpExpr struct {
+	op          string
+	left, right valueExpr
+}
+
+func (expr cmpExpr) test(root, cur *JSON5) bool {
+	a, aok := expr.left.value(root, cur)
+	b, bok := expr.right.value(root, cur)
+
+	eq := func() bool {
+		if !aok || !bok {
+			return !aok && !bok
+		}
+
+		return equalValues(a, b)
+	}
+
+	switch expr.op {
+	case "==":
+		return eq()
+	case "!=":
+		return !eq()
+	case "<":
+		return aok && bok && lessValue(a, b)
+	case "<=":
+		return aok && bok && lessValue(a, b) || eq()
+	case ">":
+		return aok && bok && lessValue(b, a)
+	case ">=":
+		return aok && bok && lessValue(b, a) || eq()
+	}
+
+	return false
+}
+
+// compareNums compare numbers a and b by exact value, ok is false if any of them is NaN
+func compareNums(a, b *JSON5) (c int, ok bool) {
+	if a.kind == NaN || b.kind == NaN {
+		return 0, false
+	}
+
+	if a.kind == Infinity || b.kind == Infinity {
+		return infSign(a) - infSign(b), true
+	}
+
+	x, okx := new(big.Rat).SetString(a.Number().String())
+	y, oky := new(big.Rat).SetString(b.Number().String())
+	if okx && oky {
+		return x.Cmp(y), true
+	}
+
+	// exponent too large for big.Rat, compare rounded values
+	return bigFloatOf(a).Cmp(bigFloatOf(b)), true
+}
+
+// bigFloatOf return number as big.Float, overflowing to ±Inf if exponent is too large for big.Float
+func bigFloatOf(num *JSON5) *big.Float {
+	if f, err := num.Number().BigFloat(); err == nil {
+		return f
+	}
+
+	f, _ := num.Number().Float64()
+
+	return new(big.Float).SetFloat64(f)
+}
+
+// infSign return 1 for Infinity, -1 for -Infinity and 0 for finite number
+func infSign(num *JSON5) int {
+	switch {
+	case num.kind != Infinity:
+		return 0
+	case num.Number().IsNegative():
+		return -1
+	}
+
+	return 1
+}
+
+// equalValues check if a and b are deep equal, numbers are compared by value
+func equalValues(a, b *JSON5) bool {
+	if a.IsNumber() && b.IsNumber() {
+		c, ok := compareNums(a, b)
+		return ok && c == 0
+	}
+
+	if a.kind != b.kind {
+		return false
+	}
+
+	switch a.kind {
+	case String:
+		return a.String() == b.String()
+	case Boolean:
+		return a.Boolean() == b.Boolean()
+	case Null:
+		return true
+	case Array:
+		x, y := a.Array(), b.Array()
+		if len(x) != len(y) {
+			return false
+		}
+
+		for i := range x {
+			if !equalValues(x[i], y[i]) {
+				return false
+			}
+		}
+
+		return true
+	case Object:
+		x, y := a.Object(), b.Object()
+		if len(x) != len(y) {
+			return false
+		}
+
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !equalValues(v, w) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// lessValue check if a < b, only numbers and strings are ordered
+func lessValue(a, b *JSON5) bool {
+	if a.IsNumber() && b.IsNumber() {
+		c, ok := compareNums(a, b)
+		return ok && c < 0
+	}
+
+	if a.kind == String && b.kind == String {
+		return a.String() < b.String()
+	}
+
+	return false
+}
+
+// Function expression types
+const (
+	valueType = iota
+	logicalType
+	nodesType
+)
+
+var funcTypes = map[string]struct {
+	args   []int
+	result int
+}{
+	"length": {[]int{valueType}, valueType},
+	"count":  {[]int{nodesType}, valueType},
+	"match":  {[]int{valueType, valueType}, logicalType},
+	"search": {[]int{valueType, valueType}, logicalType},
+	"value":  {[]int{nodesType}, valueType},
+}
+
+// funcExpr is a function call, args are valueExpr or *pathQuery by argument type
+type funcExpr struct {
+	name string
+	args []interface{}
+	// compiled pattern of match and search if it is a literal
+	re *regexp.Regexp
+}
+
+func (expr *funcExpr) valueArg(i int, root, cur *JSON5) (*JSON5, bool) {
+	return expr.args[i].(valueExpr).value(root, cur)
+}
+
+func (expr *funcExpr) nodesArg(i int, root, cur *JSON5) []Node {
+	return expr.args[i].(*pathQuery).eval(root, cur)
+}
+
+func (expr *funcExpr) value(root, cur *JSON5) (*JSON5, bool) {
+	switch expr.name {
+	case "length":
+		v, ok := expr.valueArg(0, root, cur)
+		if !ok {
+			return nil, false
+		}
+
+		switch v.kind {
+		case String:
+			return intNode(utf8.RuneCountInString(v.String())), true
+		case Array:
+			return intNode(len(v.Array())), true
+		case Object:
+			return intNode(len(v.Object())), true
+		}
+	case "count":
+		return intNode(len(expr.nodesArg(0, root, cur))), true
+	case "value":
+		nodes := expr.nodesArg(0, root, cur)
+		if len(nodes) == 1 {
+			return nodes[0].Value, true
+		}
+	}
+
+	return nil, false
+}
+
+func (expr *funcExpr) test(root, cur *JSON5) bool {
+	str, ok := expr.valueArg(0, root, cur)
+	if !ok || str.kind != String {
+		return false
+	}
+
+	re := expr.re
+	if re == nil {
+		pattern, ok := expr.valueArg(1, root, cur)
+		if !ok || pattern.kind != String {
+			return false
+		}
+
+		var err error
+		if re, err = compileIRegexp(pattern.String(), expr.name == "match"); err != nil {
+			return false
+		}
+	}
+
+	return re.MatchString(str.String())
+}
+
+// compileIRegexp compile I-Regexp (RFC 9485) pattern, full match the whole string
+func compileIRegexp(pattern string, full bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	inClass, escaped := false, false
+	for _, c := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+		case c == '.':
+			// I-Regexp dot does not match line feed nor carriage return
+			b.WriteString(`[^\n\r]`)
+			continue
+		}
+
+		b.WriteRune(c)
+	}
+
+	expr := b.String()
+	if full {
+		expr = `\A(?:` + expr + `)\z`
+	}
+
+	return regexp.Compile(expr)
+}
+
+// intNode return Integer value of n
+func intNode(n int) *JSON5 {
+	json, _ := Parse([]byte(strconv.Itoa(n)))
+	return json
+}
@@ -0,0 +1,633 @@
+package json5extract
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+)
+
+// maximum magnitude of index and slice integers, the largest integer exactly representable by float64
+const maxQueryInt = 1<<53 - 1
+
+type queryParser struct {
+	src   []rune
+	i     int
+	query string
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	return &QueryError{Query: p.query, Offset: p.i, Msg: fmt.Sprintf(format, args...)}
+}
+
+// peek return the next rune, EOFChar at the end of query
+func (p *queryParser) peek() rune {
+	if p.i < len(p.src) {
+		return p.src[p.i]
+	}
+
+	return EOFChar
+}
+
+// consume skip str if query continue with it
+func (p *queryParser) consume(str string) bool {
+	rs := []rune(str)
+	if p.i+len(rs) > len(p.src) || string(p.src[p.i:p.i+len(rs)]) != str {
+		return false
+	}
+
+	p.i += len(rs)
+
+	return true
+}
+
+func (p *queryParser) skipBlank() {
+	for {
+		switch p.peek() {
+		case ' ', '\t', '\n', '\r':
+			p.i++
+		default:
+			return
+		}
+	}
+}
+
+// parseQuery parse query beginning with $ or @
+func (p *queryParser) parseQuery() (*pathQuery, error) {
+	q := &pathQuery{relative: p.peek() == '@'}
+	p.i++
+
+	for {
+		save := p.i
+		p.skipBlank()
+
+		var seg pathSegment
+		var err error
+		switch {
+		case p.peek() == '[':
+			seg.sels, err = p.parseBracket()
+		case p.consume(".."):
+			seg.descendant = true
+			if p.peek() == '[' {
+				seg.sels, err = p.parseBracket()
+			} else {
+				seg.sels, err = p.parseShorthand()
+			}
+		case p.consume("."):
+			seg.sels, err = p.parseShorthand()
+		default:
+			p.i = save
+			return q, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		q.segs = append(q.segs, seg)
+	}
+}
+
+// parseShorthand parse wildcard or member name after dot
+func (p *queryParser) parseShorthand() ([]selector, error) {
+	if p.consume("*") {
+		return []selector{{kind: selWildcard}}, nil
+	}
+
+	start := p.i
+	for c := p.peek(); isNameChar(c, p.i == start); c = p.peek() {
+		p.i++
+	}
+
+	if p.i == start {
+		return nil, p.errorf("expected member name or '*'")
+	}
+
+	return []selector{{kind: selName, name: string(p.src[start:p.i])}}, nil
+}
+
+// isNameChar check if c can be in member name shorthand, digit can not be the first char
+func isNameChar(c rune, first bool) bool {
+	switch {
+	case c == '_' || c >= 0x80:
+		return true
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return true
+	}
+
+	return !first && c >= '0' && c <= '9'
+}
+
+// parseBracket parse comma separated selectors between brackets
+func (p *queryParser) parseBracket() ([]selector, error) {
+	p.i++
+	var sels []selector
+	for {
+		p.skipBlank()
+		sel, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+
+		sels = append(sels, sel)
+		p.skipBlank()
+		switch {
+		case p.consume(","):
+			continue
+		case p.consume("]"):
+			return sels, nil
+		}
+
+		return nil, p.errorf("expected ',' or ']'")
+	}
+}
+
+func (p *queryParser) parseSelector() (selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		str, err := p.parseStrLit()
+		if err != nil {
+			return selector{}, err
+		}
+
+		return selector{kind: selName, name: str.String()}, nil
+	case c == '*':
+		p.i++
+		return selector{kind: selWildcard}, nil
+	case c == '?':
+		p.i++
+		p.skipBlank()
+		expr, err := p.parseOr()
+		if err != nil {
+			return selector{}, err
+		}
+
+		return selector{kind: selFilter, filter: expr}, nil
+	}
+
+	// index or slice
+	var sel selector
+	for part := 0; ; part++ {
+		var n *int
+		if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+			i, err := p.parseInt()
+			if err != nil {
+				return selector{}, err
+			}
+
+			n = &i
+			p.skipBlank()
+		}
+
+		if part == 0 && p.peek() != ':' {
+			if n == nil {
+				return selector{}, p.errorf("expected selector")
+			}
+
+			return selector{kind: selIndex, index: *n}, nil
+		}
+
+		sel.kind = selSlice
+		sel.slice[part] = n
+		if part == 2 || !p.consume(":") {
+			return sel, nil
+		}
+
+		p.skipBlank()
+	}
+}
+
+// parseInt parse integer without leading zero, within ±(2^53-1)
+func (p *queryParser) parseInt() (int, error) {
+	start := p.i
+	if p.peek() == '-' {
+		p.i++
+	}
+
+	digits := p.i
+	for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+		p.i++
+	}
+
+	lit := string(p.src[start:p.i])
+	if p.i == digits || (p.src[digits] == '0' && (p.i-digits > 1 || digits > start)) {
+		p.i = start
+		return 0, p.errorf("invalid integer %q", lit)
+	}
+
+	n, err := strconv.ParseInt(lit, 10, 64)
+	if err != nil || n > maxQueryInt || n < -maxQueryInt {
+		p.i = start
+		return 0, p.errorf("integer %s out of range", lit)
+	}
+
+	return int(n), nil
+}
+
+// parseStrLit parse quoted string literal, escapes are RFC 9535 escapes, which are JSON escapes
+// and escaped quote of the literal
+func (p *queryParser) parseStrLit() (*JSON5, error) {
+	start := p.i
+	quote := p.src[p.i]
+	for p.i++; p.i < len(p.src); p.i++ {
+		c := p.src[p.i]
+		if c == quote {
+			p.i++
+			return p.parseLit(start)
+		}
+
+		if c < 0x20 {
+			return nil, p.errorf("control char %U in string", c)
+		}
+
+		if c == '\\' {
+			p.i++
+			if err := p.checkEscape(quote); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	p.i = start
+	return nil, p.errorf("unterminated string")
+}
+
+// checkEscape check escape sequence after '\\', and skip to its last char
+func (p *queryParser) checkEscape(quote rune) error {
+	switch p.peek() {
+	case 'b', 'f', 'n', 'r', 't', '/', '\\', quote:
+		return nil
+	case 'u':
+		char, ok := p.hex4(p.i + 1)
+		if !ok {
+			return p.errorf("invalid unicode escape")
+		}
+
+		if !utf16.IsSurrogate(char) {
+			p.i += 4
+			return nil
+		}
+
+		// high surrogate must be followed by escaped low surrogate
+		if char < 0xDC00 && p.i+6 < len(p.src) && p.src[p.i+5] == '\\' && p.src[p.i+6] == 'u' {
+			if low, ok := p.hex4(p.i + 7); ok && low >= 0xDC00 && low <= 0xDFFF {
+				p.i += 10
+				return nil
+			}
+		}
+
+		return p.errorf("unpaired surrogate in unicode escape")
+	}
+
+	return p.errorf("invalid escape %q", p.peek())
+}
+
+// hex4 return value of 4 hexadecimal digits at i
+func (p *queryParser) hex4(i int) (rune, bool) {
+	if i+4 > len(p.src) {
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(string(p.src[i:i+4]), 16, 16)
+	if err != nil {
+		return 0, false
+	}
+
+	return rune(n), true
+}
+
+// isQueryNumber check if lit is a number of RFC 9535, which is a JSON number
+func isQueryNumber(lit []rune) bool {
+	i := 0
+	digits := func() int {
+		from := i
+		for i < len(lit) && lit[i] >= '0' && lit[i] <= '9' {
+			i++
+		}
+
+		return i - from
+	}
+
+	if i < len(lit) && lit[i] == '-' {
+		i++
+	}
+
+	if first := i; digits() == 0 || (lit[first] == '0' && i-first > 1) {
+		return false
+	}
+
+	if i < len(lit) && lit[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+
+	if i < len(lit) && (lit[i] == 'e' || lit[i] == 'E') {
+		i++
+		if i < len(lit) && (lit[i] == '-' || lit[i] == '+') {
+			i++
+		}
+
+		if digits() == 0 {
+			return false
+		}
+	}
+
+	return i == len(lit)
+}
+
+// parseLit parse literal from start to the current offset as JSON5 value
+func (p *queryParser) parseLit(start int) (*JSON5, error) {
+	lit := string(p.src[start:p.i])
+	json, err := Parse([]byte(lit))
+	if err != nil {
+		p.i = start
+		return nil, p.errorf("invalid literal %s: %v", lit, err)
+	}
+
+	return json, nil
+}
+
+func (p *queryParser) parseOr() (logicalExpr, error) {
+	var or orExpr
+	for {
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		or = append(or, expr)
+		save := p.i
+		p.skipBlank()
+		if !p.consume("||") {
+			p.i = save
+			break
+		}
+
+		p.skipBlank()
+	}
+
+	if len(or) == 1 {
+		return or[0], nil
+	}
+
+	return or, nil
+}
+
+func (p *queryParser) parseAnd() (logicalExpr, error) {
+	var and andExpr
+	for {
+		expr, err := p.parseBasic()
+		if err != nil {
+			return nil, err
+		}
+
+		and = append(and, expr)
+		save := p.i
+		p.skipBlank()
+		if !p.consume("&&") {
+			p.i = save
+			break
+		}
+
+		p.skipBlank()
+	}
+
+	if len(and) == 1 {
+		return and[0], nil
+	}
+
+	return and, nil
+}
+
+// parseBasic parse parenthesized expression, comparison or test, optionally negated
+func (p *queryParser) parseBasic() (logicalExpr, error) {
+	if p.consume("!") {
+		p.skipBlank()
+		if p.peek() != '(' {
+			start := p.i
+			o, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+
+			expr, err := p.testExpr(o, start)
+			if err != nil {
+				return nil, err
+			}
+
+			return notExpr{expr}, nil
+		}
+
+		expr, err := p.parseBasic()
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpr{expr}, nil
+	}
+
+	if p.consume("(") {
+		p.skipBlank()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipBlank()
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+
+		return expr, nil
+	}
+
+	start := p.i
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	save := p.i
+	p.skipBlank()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.consume(op) {
+			continue
+		}
+
+		l, err := p.comparable(left, start)
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipBlank()
+		start := p.i
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := p.comparable(right, start)
+		if err != nil {
+			return nil, err
+		}
+
+		return cmpExpr{op: op, left: l, right: r}, nil
+	}
+
+	p.i = save
+
+	return p.testExpr(left, start)
+}
+
+// parseOperand parse literal, query or function call, the result is *JSON5, *pathQuery or *funcExpr
+func (p *queryParser) parseOperand() (interface{}, error) {
+	start := p.i
+	switch c := p.peek(); {
+	case c == '$' || c == '@':
+		return p.parseQuery()
+	case c == '\'' || c == '"':
+		return p.parseStrLit()
+	case c == '-' || (c >= '0' && c <= '9'):
+		for c := p.peek(); c == '-' || c == '+' || c == '.' || isNameChar(c, false); c = p.peek() {
+			p.i++
+		}
+
+		if lit := p.src[start:p.i]; !isQueryNumber(lit) {
+			p.i = start
+			return nil, p.errorf("invalid number %q", string(lit))
+		}
+
+		return p.parseLit(start)
+	case c >= 'a' && c <= 'z':
+		for c := p.peek(); c == '_' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9'); c = p.peek() {
+			p.i++
+		}
+
+		name := string(p.src[start:p.i])
+		if p.peek() != '(' {
+			switch name {
+			case "true", "false", "null":
+				return p.parseLit(start)
+			}
+
+			p.i = start
+			return nil, p.errorf("unknown literal %q", name)
+		}
+
+		return p.parseFunc(name, start)
+	}
+
+	return nil, p.errorf("expected literal, query or function")
+}
+
+// parseFunc parse arguments of function name and check their types
+func (p *queryParser) parseFunc(name string, start int) (*funcExpr, error) {
+	typ, ok := funcTypes[name]
+	if !ok {
+		p.i = start
+		return nil, p.errorf("unknown function %s", name)
+	}
+
+	p.i++
+	fn := &funcExpr{name: name}
+	for {
+		p.skipBlank()
+		if len(fn.args) == 0 && p.consume(")") {
+			break
+		}
+
+		argStart := p.i
+		o, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(fn.args) >= len(typ.args) {
+			p.i = argStart
+			return nil, p.errorf("too many arguments of %s", name)
+		}
+
+		arg, err := p.funcArg(o, typ.args[len(fn.args)], argStart)
+		if err != nil {
+			return nil, err
+		}
+
+		fn.args = append(fn.args, arg)
+		p.skipBlank()
+		if p.consume(",") {
+			continue
+		}
+
+		if p.consume(")") {
+			break
+		}
+
+		return nil, p.errorf("expected ',' or ')'")
+	}
+
+	if len(fn.args) != len(typ.args) {
+		p.i = start
+		return nil, p.errorf("%s take %d argument(s)", name, len(typ.args))
+	}
+
+	if lit, ok := fn.args[len(fn.args)-1].(literalExpr); ok && typ.result == logicalType && lit.val.kind == String {
+		if re, err := compileIRegexp(lit.val.String(), name == "match"); err == nil {
+			fn.re = re
+		}
+	}
+
+	return fn, nil
+}
+
+// funcArg convert operand to function argument of type typ
+func (p *queryParser) funcArg(o interface{}, typ int, start int) (interface{}, error) {
+	if typ == valueType {
+		return p.comparable(o, start)
+	}
+
+	if q, ok := o.(*pathQuery); ok {
+		return q, nil
+	}
+
+	p.i = start
+	return nil, p.errorf("expected query argument")
+}
+
+// comparable convert operand to value expression, which is a literal, singular query or function
+// returning a value
+func (p *queryParser) comparable(o interface{}, start int) (valueExpr, error) {
+	switch o := o.(type) {
+	case *JSON5:
+		return literalExpr{o}, nil
+	case *pathQuery:
+		if o.singular() {
+			return singularExpr{o}, nil
+		}
+
+		p.i = start
+		return nil, p.errorf("query compared or passed as value must be singular")
+	case *funcExpr:
+		if funcTypes[o.name].result == valueType {
+			return o, nil
+		}
+	}
+
+	p.i = start
+	return nil, p.errorf("expected value")
+}
+
+// testExpr convert operand to existence test or function returning logical
+func (p *queryParser) testExpr(o interface{}, start int) (logicalExpr, error) {
+	switch o := o.(type) {
+	case *pathQuery:
+		return existExpr{o}, nil
+	case *funcExpr:
+		if funcTypes[o.name].result == logicalType {
+			return o, nil
+		}
+	}
+
+	p.i = start
+	return nil, p.errorf("expected comparison, query or logical function")
+}
@@ -0,0 +1,323 @@
+package json5extract
+
+import (
+	"strings"
+	"testing"
+)
+
+// examples of RFC 9535
+const (
+	bookstore = `{ "store": {
+    "book": [
+      { "category": "reference",
+        "author": "Nigel Rees",
+        "title": "Sayings of the Century",
+        "price": 8.95
+      },
+      { "category": "fiction",
+        "author": "Evelyn Waugh",
+        "title": "Sword of Honour",
+        "price": 12.99
+      },
+      { "category": "fiction",
+        "author": "Herman Melville",
+        "title": "Moby Dick",
+        "isbn": "0-553-21311-3",
+        "price": 8.99
+      },
+      { "category": "fiction",
+        "author": "J. R. R. Tolkien",
+        "title": "The Lord of the Rings",
+        "isbn": "0-395-19395-8",
+        "price": 22.99
+      }
+    ],
+    "bicycle": {
+      "color": "red",
+      "price": 399
+    }
+  }
+}`
+	filterDoc = `{
+  "a": [3, 5, 1, 2, 4, 6,
+        {"b": "j"},
+        {"b": "k"},
+        {"b": {}},
+        {"b": "kilo"}
+       ],
+  "o": {"p": 1, "q": 2, "r": 3, "s": 5, "t": {"u": 6}},
+  "e": "f"
+}`
+	nameDoc       = `{"o": {"j j": {"k.k": 3}}, "'": {"@": 2}}`
+	wildcardDoc   = `{"o": {"j": 1, "k": 2}, "a": [5, 3]}`
+	sliceDoc      = `["a", "b", "c", "d", "e", "f", "g"]`
+	descendantDoc = `{"o": {"j": 1, "k": 2}, "a": [5, 3, [{"j": 4}, {"k": 6}]]}`
+	nullDoc       = `{"a": null, "b": [null], "c": [{}], "null": 1}`
+)
+
+type queryTest struct {
+	doc   string
+	query string
+	// normalized paths of selected nodes
+	paths []string
+	// compact values of selected nodes, not checked if nil
+	values []string
+}
+
+var queryTests = []queryTest{
+	// overview, table 2
+	{bookstore, `$.store.book[*].author`,
+		[]string{`$['store']['book'][0]['author']`, `$['store']['book'][1]['author']`, `$['store']['book'][2]['author']`, `$['store']['book'][3]['author']`},
+		[]string{`"Nigel Rees"`, `"Evelyn Waugh"`, `"Herman Melville"`, `"J. R. R. Tolkien"`}},
+	{bookstore, `$..author`,
+		[]string{`$['store']['book'][0]['author']`, `$['store']['book'][1]['author']`, `$['store']['book'][2]['author']`, `$['store']['book'][3]['author']`}, nil},
+	{bookstore, `$.store.*`, []string{`$['store']['book']`, `$['store']['bicycle']`}, nil},
+	{bookstore, `$.store..price`,
+		[]string{`$['store']['book'][0]['price']`, `$['store']['book'][1]['price']`, `$['store']['book'][2]['price']`, `$['store']['book'][3]['price']`, `$['store']['bicycle']['price']`},
+		[]string{`8.95`, `12.99`, `8.99`, `22.99`, `399`}},
+	{bookstore, `$..book[2]`, []string{`$['store']['book'][2]`}, nil},
+	{bookstore, `$..book[2].author`, []string{`$['store']['book'][2]['author']`}, []string{`"Herman Melville"`}},
+	{bookstore, `$..book[2].publisher`, []string{}, nil},
+	{bookstore, `$..book[-1]`, []string{`$['store']['book'][3]`}, nil},
+	{bookstore, `$..book[0,1]`, []string{`$['store']['book'][0]`, `$['store']['book'][1]`}, nil},
+	{bookstore, `$..book[:2]`, []string{`$['store']['book'][0]`, `$['store']['book'][1]`}, nil},
+	{bookstore, `$..book[?@.isbn]`, []string{`$['store']['book'][2]`, `$['store']['book'][3]`}, nil},
+	{bookstore, `$..book[?@.price<10]`, []string{`$['store']['book'][0]`, `$['store']['book'][2]`}, nil},
+	{bookstore, `$..book[?@.price == 8.95e0]`, []string{`$['store']['book'][0]`}, nil},
+
+	// name selector, 2.3.1.3
+	{nameDoc, `$.o['j j']`, []string{`$['o']['j j']`}, []string{`{"k.k":3}`}},
+	{nameDoc, `$.o['j j']['k.k']`, []string{`$['o']['j j']['k.k']`}, []string{`3`}},
+	{nameDoc, `$.o["j j"]["k.k"]`, []string{`$['o']['j j']['k.k']`}, []string{`3`}},
+	{nameDoc, `$["'"]["@"]`, []string{`$['\'']['@']`}, []string{`2`}},
+
+	// wildcard selector, 2.3.2.3
+	{wildcardDoc, `$[*]`, []string{`$['o']`, `$['a']`}, []string{`{"j":1,"k":2}`, `[5,3]`}},
+	{wildcardDoc, `$.o[*]`, []string{`$['o']['j']`, `$['o']['k']`}, []string{`1`, `2`}},
+	{wildcardDoc, `$.o[*, *]`, []string{`$['o']['j']`, `$['o']['k']`, `$['o']['j']`, `$['o']['k']`}, nil},
+	{wildcardDoc, `$.a[*]`, []string{`$['a'][0]`, `$['a'][1]`}, []string{`5`, `3`}},
+
+	// index selector, 2.3.3.3
+	{`["a","b"]`, `$[1]`, []string{`$[1]`}, []string{`"b"`}},
+	{`["a","b"]`, `$[-2]`, []string{`$[0]`}, []string{`"a"`}},
+	{`["a","b"]`, `$[2]`, []string{}, nil},
+	{`{"0": 1}`, `$[0]`, []string{}, nil},
+
+	// array slice selector, 2.3.4.3
+	{sliceDoc, `$[1:3]`, []string{`$[1]`, `$[2]`}, nil},
+	{sliceDoc, `$[5:]`, []string{`$[5]`, `$[6]`}, nil},
+	{sliceDoc, `$[1:5:2]`, []string{`$[1]`, `$[3]`}, nil},
+	{sliceDoc, `$[5:1:-2]`, []string{`$[5]`, `$[3]`}, nil},
+	{sliceDoc, `$[::-1]`, []string{`$[6]`, `$[5]`, `$[4]`, `$[3]`, `$[2]`, `$[1]`, `$[0]`}, nil},
+	{sliceDoc, `$[1:3:0]`, []string{}, nil},
+	{sliceDoc, `$[-2:]`, []string{`$[5]`, `$[6]`}, nil},
+	{sliceDoc, `$[-100:2]`, []string{`$[0]`, `$[1]`}, nil},
+	{sliceDoc, `$[:-100:-3]`, []string{`$[6]`, `$[3]`, `$[0]`}, nil},
+	{sliceDoc, `$[3:1]`, []string{}, nil},
+
+	// filter selector, 2.3.5.3
+	{filterDoc, `$.a[?@.b == 'kilo']`, []string{`$['a'][9]`}, []string{`{"b":"kilo"}`}},
+	{filterDoc, `$.a[?(@.b == 'kilo')]`, []string{`$['a'][9]`}, nil},
+	{filterDoc, `$.a[?@>3.5]`, []string{`$['a'][1]`, `$['a'][4]`, `$['a'][5]`}, []string{`5`, `4`, `6`}},
+	{filterDoc, `$.a[?@.b]`, []string{`$['a'][6]`, `$['a'][7]`, `$['a'][8]`, `$['a'][9]`}, nil},
+	{filterDoc, `$[?@.*]`, []string{`$['a']`, `$['o']`}, nil},
+	{filterDoc, `$[?@[?@.b]]`, []string{`$['a']`}, nil},
+	{filterDoc, `$.o[?@<3, ?@<3]`, []string{`$['o']['p']`, `$['o']['q']`, `$['o']['p']`, `$['o']['q']`}, nil},
+	{filterDoc, `$.a[?@<2 || @.b == "k"]`, []string{`$['a'][2]`, `$['a'][7]`}, nil},
+	{filterDoc, `$.a[?match(@.b, "[jk]")]`, []string{`$['a'][6]`, `$['a'][7]`}, nil},
+	{filterDoc, `$.a[?search(@.b, "[jk]")]`, []string{`$['a'][6]`, `$['a'][7]`, `$['a'][9]`}, nil},
+	{filterDoc, `$.o[?@>1 && @<4]`, []string{`$['o']['q']`, `$['o']['r']`}, nil},
+	{filterDoc, `$.o[?@.u || @.x]`, []string{`$['o']['t']`}, nil},
+	{filterDoc, `$.a[?@.b == $.x]`, []string{`$['a'][0]`, `$['a'][1]`, `$['a'][2]`, `$['a'][3]`, `$['a'][4]`, `$['a'][5]`}, nil},
+	{filterDoc, `$.a[?@ == @]`,
+		[]string{`$['a'][0]`, `$['a'][1]`, `$['a'][2]`, `$['a'][3]`, `$['a'][4]`, `$['a'][5]`, `$['a'][6]`, `$['a'][7]`, `$['a'][8]`, `$['a'][9]`}, nil},
+	{filterDoc, `$.a[?!@.b]`, []string{`$['a'][0]`, `$['a'][1]`, `$['a'][2]`, `$['a'][3]`, `$['a'][4]`, `$['a'][5]`}, nil},
+	{filterDoc, `$.a[?@ > 1 && @ < 4 || @.b == 'j']`, []string{`$['a'][0]`, `$['a'][3]`, `$['a'][6]`}, nil},
+	{filterDoc, `$.a[?!(@ > 1 && @ < 4) && @ <= 5]`, []string{`$['a'][1]`, `$['a'][2]`, `$['a'][4]`}, nil},
+	{filterDoc, `$.a[?@.b == 'k' || @.b == 'kilo']`, []string{`$['a'][7]`, `$['a'][9]`}, nil},
+
+	// descendant segment, 2.5.2.3
+	{descendantDoc, `$..j`, []string{`$['o']['j']`, `$['a'][2][0]['j']`}, []string{`1`, `4`}},
+	{descendantDoc, `$..[0]`, []string{`$['a'][0]`, `$['a'][2][0]`}, []string{`5`, `{"j":4}`}},
+	{descendantDoc, `$..o`, []string{`$['o']`}, nil},
+	{descendantDoc, `$.o..[*, *]`, []string{`$['o']['j']`, `$['o']['k']`, `$['o']['j']`, `$['o']['k']`}, nil},
+	{descendantDoc, `$.a..[0, 1]`, []string{`$['a'][0]`, `$['a'][1]`, `$['a'][2][0]`, `$['a'][2][1]`}, nil},
+	{descendantDoc, `$..[*]`,
+		[]string{`$['o']`, `$['a']`, `$['o']['j']`, `$['o']['k']`, `$['a'][0]`, `$['a'][1]`, `$['a'][2]`,
+			`$['a'][2][0]`, `$['a'][2][1]`, `$['a'][2][0]['j']`, `$['a'][2][1]['k']`}, nil},
+
+	// null semantics, 2.6.1
+	{nullDoc, `$.a`, []string{`$['a']`}, []string{`null`}},
+	{nullDoc, `$.a[0]`, []string{}, nil},
+	{nullDoc, `$.a.d`, []string{}, nil},
+	{nullDoc, `$.b[0]`, []string{`$['b'][0]`}, []string{`null`}},
+	{nullDoc, `$.b[*]`, []string{`$['b'][0]`}, []string{`null`}},
+	{nullDoc, `$.b[?@]`, []string{`$['b'][0]`}, nil},
+	{nullDoc, `$.b[?@==null]`, []string{`$['b'][0]`}, nil},
+	{nullDoc, `$.c[?@.d==null]`, []string{}, nil},
+	{nullDoc, `$.null`, []string{`$['null']`}, []string{`1`}},
+
+	// function extensions, 2.4
+	{bookstore, `$.store.book[?length(@.author) > 14]`, []string{`$['store']['book'][2]`, `$['store']['book'][3]`}, nil},
+	{bookstore, `$.store[?length(@) == 2]`, []string{`$['store']['bicycle']`}, nil},
+	{bookstore, `$.store.book[?count(@.*) == 5]`, []string{`$['store']['book'][2]`, `$['store']['book'][3]`}, nil},
+	{bookstore, `$.store[?value(@..color) == "red"]`, []string{`$['store']['bicycle']`}, nil},
+	{bookstore, `$.store.book[?match(@.author, '.*Tolkien')].title`, []string{`$['store']['book'][3]['title']`}, nil},
+	{`["a\nb", "ab", "xab"]`, `$[?match(@, 'a.b')]`, []string{}, nil},
+	{`["a\nb", "axb", "xaxb"]`, `$[?search(@, 'a.b')]`, []string{`$[1]`, `$[2]`}, nil},
+	{`["é", "ée", "e"]`, `$[?length(@) == 1]`, []string{`$[0]`, `$[2]`}, nil},
+
+	// normalized paths, 2.7
+	{`{"a": 1}`, `$.a`, []string{`$['a']`}, nil},
+	{`["a", "b", "c"]`, `$[-1]`, []string{`$[2]`}, nil},
+	{`{"a": {"b": [1, 2]}}`, `$.a.b[1:2]`, []string{`$['a']['b'][1]`}, nil},
+	{`{"\u000B": 1}`, `$["\u000B"]`, []string{`$['\u000b']`}, nil},
+	{`{"a": 1}`, `$["\u0061"]`, []string{`$['a']`}, nil},
+	{`{"\\'\b\f\n\r\t": 1}`, `$.*`, []string{`$['\\\'\b\f\n\r\t']`}, nil},
+
+	// JSON5 values and literals
+	{`{a: [0x10, +1, .5, Infinity, -Infinity, NaN]}`, `$.a[?@ > 1]`, []string{`$['a'][0]`, `$['a'][3]`}, nil},
+	{`{a: [0x10, +1, .5, Infinity, -Infinity, NaN]}`, `$.a[?@ == 16]`, []string{`$['a'][0]`}, nil},
+	{`{a: [0x10, +1, .5, Infinity, -Infinity, NaN]}`, `$.a[?@ < -1e308]`, []string{`$['a'][4]`}, nil},
+	{`{a: [0x10, +1, .5, Infinity, -Infinity, NaN]}`, `$.a[?@ == @]`, []string{`$['a'][0]`, `$['a'][1]`, `$['a'][2]`, `$['a'][3]`, `$['a'][4]`}, nil},
+	{`[1e99999999, -1e99999999, 7]`, `$[?@ > 5]`, []string{`$[0]`, `$[2]`}, nil},
+	{`{'single': 1}`, `$.single`, []string{`$['single']`}, nil},
+}
+
+func TestQuery(t *testing.T) {
+	for _, test := range queryTests {
+		json, err := Parse([]byte(test.doc))
+		if err != nil {
+			t.Fatalf("%s: %v", test.query, err)
+		}
+
+		nodes, err := json.Query(test.query)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.query, err)
+			continue
+		}
+
+		paths := make([]string, len(nodes))
+		values := make([]string, len(nodes))
+		for i, n := range nodes {
+			paths[i] = n.Path.Normalized()
+			values[i] = string(n.Value.Compact())
+
+			// path locate the node
+			if v, err := json.Pointer(n.Path.Pointer()); err != nil || v != n.Value {
+				t.Errorf("%s: path %s does not locate the node", test.query, paths[i])
+			}
+		}
+
+		if !equalStrs(paths, test.paths) {
+			t.Errorf("%s: got paths %q, want %q", test.query, paths, test.paths)
+		}
+
+		if test.values != nil && !equalStrs(values, test.values) {
+			t.Errorf("%s: got values %q, want %q", test.query, values, test.values)
+		}
+	}
+}
+
+func TestQueryInvalid(t *testing.T) {
+	queries := []string{
+		``,
+		`store`,
+		` $`,
+		`$ `,
+		`$.`,
+		`$..`,
+		`$[`,
+		`$[]`,
+		`$['a'`,
+		`$['a',]`,
+		`$[01]`,
+		`$[-0]`,
+		`$[1.0]`,
+		`$[9007199254740992]`,
+		`$[1:2:3:4]`,
+		`$.a[?@.b`,
+		`$[?@.a ==]`,
+		`$[?@ = 1]`,
+		`$[?@.a == @.*]`,
+		`$[?@..a == 1]`,
+		`$[?(@.a]`,
+		`$[?length(@.*) < 3]`,
+		`$[?count(1) == 1]`,
+		`$[?count(foo(@.*)) == 1]`,
+		`$[?match(@.timezone, 'Europe/.*') == true]`,
+		`$[?value(@..color)]`,
+		`$[?length(@)]`,
+		`$[?foo(@.a)]`,
+		`$[?1]`,
+		`$[?'a' == 'a' && 1]`,
+		`$.a b`,
+		// JSON5 escapes and numbers are not RFC 9535 literals
+		`$['\q']`,
+		`$['\x41']`,
+		`$['\0']`,
+		`$["\'"]`,
+		`$['\"']`,
+		`$['\u00']`,
+		`$['\uD83D']`,
+		`$['\uDE00\uD83D']`,
+		"$['a\tb']",
+		`$[?@ == 0x10]`,
+		`$[?@ == +1]`,
+		`$[?@ == .5]`,
+		`$[?@ == 5.]`,
+		`$[?@ == 01]`,
+		`$[?@ == 1e]`,
+		`$[?@ == Infinity]`,
+		`$[?@ == -Infinity]`,
+		`$[?@ == NaN]`,
+	}
+
+	for _, query := range queries {
+		_, err := CompileJSONPath(query)
+		if _, ok := err.(*QueryError); !ok {
+			t.Errorf("%q: got %v, want QueryError", query, err)
+		}
+	}
+}
+
+func TestQueryValid(t *testing.T) {
+	queries := []string{
+		`$`,
+		`$[?length(@) < 3]`,
+		`$[?count(@.*) == 1]`,
+		`$[?match(@.timezone, 'Europe/.*')]`,
+		`$[?value(@..color) == "red"]`,
+		`$[ 'a' , 1 , * , 1:2 , ?@ ]`,
+		`$[?@.a==1&&@.b!=2||!@.c]`,
+		`$[?@.a <= $.b[0]]`,
+		`$.ünïcode`,
+		`$["\uD83D\uDE00"]`,
+		`$['\'"\b\f\n\r\t\/\\\u00e9']`,
+		`$["'\""]`,
+		`$[?@.a == -0 || @.a == 0.5e-3 || @.a == -12E+2]`,
+	}
+
+	for _, query := range queries {
+		path, err := CompileJSONPath(query)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", query, err)
+			continue
+		}
+
+		if path.String() != query {
+			t.Errorf("%q: String return %q", query, path.String())
+		}
+	}
+}
+
+func TestQueryReader(t *testing.T) {
+	nodes, err := Query(strings.NewReader(bookstore), `$..book[?@.price > 20].title`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(nodes) != 1 || nodes[0].Value.String() != "The Lord of the Rings" {
+		t.Errorf("got %v, want the title of the fourth book", nodes)
+	}
+}
@@ -3,7 +3,7 @@ package json5extract
 import (
 	"io"
 	"math"
-	"strconv"
+	"strings"
 	"unicode"
 )
 
@@ -14,6 +14,7 @@ type numStates struct {
 	withExp    bool
 	isPositive bool
 	isNegative bool
+	withSign   bool
 	isInfinity bool
 	isNan      bool
 }
@@ -25,8 +26,66 @@ var (
 	nan = []rune("aN")
 )
 
-func parseNum(r reader, firstC rune) (*JSON5, error) {
-	num := new(JSON5)
+func parseNum(r *reader, firstC rune) (*JSON5, error) {
+	start := r.lastPos()
+	num, state, err := scanNum(r, firstC)
+	if err != nil {
+		return nil, err
+	}
+
+	num.num = newNumber(num, state)
+
+	var rangeErr error
+	switch num.kind {
+	case Integer:
+		num.val, rangeErr = num.Number().Int64()
+	case Float:
+		num.val, rangeErr = num.Number().Float64()
+	}
+
+	if rangeErr != nil && r.opts.RejectNumberOverflow {
+		return nil, newSyntaxError(CodeNumberRange, start, num.raw[0], "")
+	}
+
+	return num, nil
+}
+
+// newNumber return Number of scanned num
+func newNumber(num *JSON5, state *numStates) *Number {
+	n := &Number{
+		lit:  string(num.raw),
+		kind: num.kind,
+		hex:  state.isHex,
+		sign: state.withSign,
+		neg:  state.isNegative,
+		exp:  state.withExp,
+	}
+
+	if n.kind != Integer && n.kind != Float {
+		return n
+	}
+
+	_, unsigned := n.split()
+	mantissa := unsigned
+	if !n.hex {
+		if i := strings.IndexAny(unsigned, "eE"); i >= 0 {
+			mantissa = unsigned[:i]
+		}
+
+		n.leadingPoint = strings.HasPrefix(mantissa, ".")
+		n.trailingPoint = strings.HasSuffix(mantissa, ".")
+	} else {
+		mantissa = mantissa[2:]
+	}
+
+	n.negZero = n.neg && strings.Trim(mantissa, "0.") == ""
+
+	return n
+}
+
+// scanNum scan number literal and set its kind, value of Integer and Float is left unset
+func scanNum(r *reader, firstC rune) (*JSON5, *numStates, error) {
+	num := &JSON5{kind: Integer}
 	num.push(firstC)
 	state := new(numStates)
 
@@ -39,17 +98,19 @@ func parseNum(r reader, firstC rune) (*JSON5, error) {
 			state.isNegative = true
 		}
 
+		state.withSign = true
+
 		char, _, err := r.ReadRune()
 		if err != nil {
-			return nil, err
+			return nil, nil, r.readErr(err, "digit, Infinity or NaN")
 		}
 
 		if isMinOrPlusSign(char) {
-			return nil, ErrInvalidFormat
+			return nil, nil, r.syntaxErr(CodeInvalidNumber, "digit, Infinity or NaN")
 		}
 
 		if unicode.IsControl(char) || char == ' ' {
-			return nil, ErrInvalidFormat
+			return nil, nil, r.syntaxErr(CodeInvalidNumber, "digit, Infinity or NaN")
 		}
 
 		num.push(char)
@@ -57,23 +118,23 @@ func parseNum(r reader, firstC rune) (*JSON5, error) {
 	}
 
 	if !isCharNumBegin(firstC) {
-		return nil, ErrInvalidFormat
+		return nil, nil, r.syntaxErr(CodeInvalidNumber, "digit, Infinity or NaN")
 	}
 
 	if firstC == '0' {
 		char, _, err := r.ReadRune()
 		if err != nil {
 			if err == io.EOF {
-				return num, nil
+				return num, state, nil
 			}
 
-			return nil, err
+			return nil, nil, err
 		}
 
 		// end of number
-		if isCharEndOfNum(char) {
+		if isCharEndOfNum(r, char) {
 			r.UnreadRune()
-			return num, nil
+			return num, state, nil
 		}
 
 		if char == '.' {
@@ -83,33 +144,33 @@ func parseNum(r reader, firstC rune) (*JSON5, error) {
 
 			char, _, err := r.ReadRune()
 			if err != nil {
-				return nil, err
+				return nil, nil, r.readErr(err, "digit")
 			}
 
 			num.push(char)
 
 			if !unicode.IsNumber(char) {
-				return nil, ErrInvalidFormat
+				return nil, nil, r.syntaxErr(CodeInvalidNumber, "digit")
 			}
 
 			if err := parseOnlyNum(r, num, state); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
-			return num, nil
+			return num, state, nil
 		}
 
 		if char == 'e' || char == 'E' {
 			num.push(char)
 			if err := parseExp(r, num, state); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			if err := parseOnlyNum(r, num, state); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
-			return num, nil
+			return num, state, nil
 		}
 
 		// detect hexa number
@@ -117,39 +178,39 @@ func parseNum(r reader, firstC rune) (*JSON5, error) {
 			num.push(char)
 			char, _, err := r.ReadRune()
 			if err != nil {
-				return nil, err
+				return nil, nil, r.readErr(err, "hex digit")
 			}
 
 			num.push(char)
 			if !isCharHex(char) {
-				return nil, ErrInvalidFormat
+				return nil, nil, r.syntaxErr(CodeInvalidNumber, "hex digit")
 			}
 
 			state.isHex = true
 			if err := parseOnlyHex(r, num); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
-			return num, nil
+			return num, state, nil
 		}
 
 		// end of number
-		if isCharEndOfNum(char) {
+		if isCharEndOfNum(r, char) {
 			r.UnreadRune()
-			return num, nil
+			return num, state, nil
 		}
 
-		return nil, ErrInvalidFormat
+		return nil, nil, r.syntaxErr(CodeInvalidNumber, "'.', 'e', 'x' or end of number")
 	}
 
 	if firstC == '.' {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			return nil, err
+			return nil, nil, r.readErr(err, "digit")
 		}
 
 		if !unicode.IsNumber(char) {
-			return nil, ErrInvalidFormat
+			return nil, nil, r.syntaxErr(CodeInvalidNumber, "digit")
 		}
 
 		num.push(char)
@@ -157,19 +218,19 @@ func parseNum(r reader, firstC rune) (*JSON5, error) {
 		state.isInt = false
 
 		if err := parseOnlyNum(r, num, state); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	if unicode.IsNumber(firstC) {
 		if err := parseOnlyNum(r, num, state); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	if firstC == 'I' {
 		if err := parseInf(r, num, state); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		state.isInfinity = true
@@ -177,16 +238,16 @@ func parseNum(r reader, firstC rune) (*JSON5, error) {
 
 	if firstC == 'N' {
 		if err := parseNaN(r, num, state); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		state.isNan = true
 	}
 
-	return num, nil
+	return num, state, nil
 }
 
-func parseOnlyNum(r reader, num *JSON5, state *numStates) error {
+func parseOnlyNum(r *reader, num *JSON5, state *numStates) error {
 	for {
 		char, _, err := r.ReadRune()
 		if err != nil {
@@ -200,7 +261,7 @@ func parseOnlyNum(r reader, num *JSON5, state *numStates) error {
 		if !unicode.IsNumber(char) {
 			if char == '.' {
 				if state.isFloat {
-					return ErrInvalidFormat
+					return r.syntaxErr(CodeInvalidNumber, "digit or end of number")
 				}
 
 				num.push(char)
@@ -211,7 +272,7 @@ func parseOnlyNum(r reader, num *JSON5, state *numStates) error {
 
 			if char == 'e' || char == 'E' {
 				if state.withExp {
-					return ErrInvalidFormat
+					return r.syntaxErr(CodeInvalidNumber, "digit or end of number")
 				}
 
 				num.push(char)
@@ -227,39 +288,25 @@ func parseOnlyNum(r reader, num *JSON5, state *numStates) error {
 			}
 
 			// end of number
-			if isCharEndOfNum(char) {
+			if isCharEndOfNum(r, char) {
 				r.UnreadRune()
 				break
 			}
 
-			return ErrInvalidFormat
+			return r.syntaxErr(CodeInvalidNumber, "digit or end of number")
 		}
 
 		num.push(char)
 	}
 
-	if state.isFloat {
-		i, _ := strconv.ParseFloat(string(num.raw), 64)
+	if state.isFloat || state.withExp {
 		num.kind = Float
-		num.val = i
-	}
-
-	if state.isInt {
-		if state.withExp {
-			i, _ := strconv.ParseFloat(string(num.raw), 64)
-			num.kind = Float
-			num.val = i
-		}
-
-		i, _ := strconv.ParseInt(string(num.raw), 10, 64)
-		num.kind = Integer
-		num.val = i
 	}
 
 	return nil
 }
 
-func parseOnlyHex(r reader, num *JSON5) error {
+func parseOnlyHex(r *reader, num *JSON5) error {
 	for {
 		char, _, err := r.ReadRune()
 		if err != nil {
@@ -271,34 +318,27 @@ func parseOnlyHex(r reader, num *JSON5) error {
 		}
 
 		if !isCharHex(char) {
-			if char == ']' || char == '}' || char == ',' {
+			// end of number
+			if isCharEndOfNum(r, char) {
 				r.UnreadRune()
 				break
 			}
 
-			if unicode.IsControl(char) || char == ' ' {
-				break
-			}
-
-			return ErrInvalidFormat
+			return r.syntaxErr(CodeInvalidNumber, "hex digit or end of number")
 		}
 
 		num.push(char)
 	}
 
-	i, _ := strconv.ParseInt(string(num.raw), 0, 64)
-	num.val = i
-	num.kind = Integer
-
 	return nil
 }
 
 // parse exponent
-func parseExp(r reader, num *JSON5, state *numStates) error {
+func parseExp(r *reader, num *JSON5, state *numStates) error {
 	for i := 0; i < 2; i++ {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			return err
+			return r.readErr(err, "exponent digit")
 		}
 
 		num.push(char)
@@ -313,13 +353,13 @@ func parseExp(r reader, num *JSON5, state *numStates) error {
 
 			if isCharHex(char) {
 				if !state.isHex {
-					return ErrInvalidFormat
+					return r.syntaxErr(CodeInvalidNumber, "exponent digit")
 				}
 
 				break
 			}
 
-			return ErrInvalidFormat
+			return r.syntaxErr(CodeInvalidNumber, "exponent digit")
 		}
 
 		if i == 1 {
@@ -329,13 +369,13 @@ func parseExp(r reader, num *JSON5, state *numStates) error {
 
 			if isCharHex(char) {
 				if !state.isHex {
-					return ErrInvalidFormat
+					return r.syntaxErr(CodeInvalidNumber, "exponent digit")
 				}
 
 				break
 			}
 
-			return ErrInvalidFormat
+			return r.syntaxErr(CodeInvalidNumber, "exponent digit")
 		}
 	}
 
@@ -343,15 +383,15 @@ func parseExp(r reader, num *JSON5, state *numStates) error {
 	return nil
 }
 
-func parseInf(r reader, num *JSON5, state *numStates) error {
+func parseInf(r *reader, num *JSON5, state *numStates) error {
 	for _, c := range inf {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			return err
+			return r.readErr(err, "Infinity")
 		}
 
 		if char != c {
-			return ErrInvalidFormat
+			return r.syntaxErr(CodeInvalidLiteral, "Infinity")
 		}
 
 		num.push(char)
@@ -368,15 +408,15 @@ func parseInf(r reader, num *JSON5, state *numStates) error {
 	return nil
 }
 
-func parseNaN(r reader, num *JSON5, state *numStates) error {
+func parseNaN(r *reader, num *JSON5, state *numStates) error {
 	for _, c := range nan {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			return err
+			return r.readErr(err, "NaN")
 		}
 
 		if char != c {
-			return ErrInvalidFormat
+			return r.syntaxErr(CodeInvalidLiteral, "NaN")
 		}
 
 		num.push(char)
@@ -401,7 +441,9 @@ func isMinOrPlusSign(char rune) bool {
 	return false
 }
 
-func isCharEndOfNum(char rune) bool {
+// check if char terminates a number. With word boundary option, any char which is
+// neither identifier char nor decimal point terminates a number
+func isCharEndOfNum(r *reader, char rune) bool {
 	if unicode.IsControl(char) {
 		return true
 	}
@@ -411,5 +453,9 @@ func isCharEndOfNum(char rune) bool {
 		return true
 	}
 
+	if r.opts.WordBoundary && !isCharIDValid(char, false) && char != '.' {
+		return true
+	}
+
 	return false
 }
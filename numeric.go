@@ -0,0 +1,296 @@
+package json5extract
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Number is a number literal kept as written in the source, so it can be converted
+// without loss to any numeric type. It also describe how the literal was written
+type Number struct {
+	lit  string
+	kind int
+	// hexadecimal
+	hex bool
+	// explicit sign
+	sign bool
+	neg  bool
+	exp  bool
+	// decimal point without digit before or after it
+	leadingPoint  bool
+	trailingPoint bool
+	negZero       bool
+}
+
+// Number return number of Integer, Float, Infinity or NaN value. Will panic if kind is not one of them
+func (json *JSON5) Number() Number {
+	if json.num == nil {
+		panic("value is not number")
+	}
+
+	return *json.num
+}
+
+// IsNumber check if kind is Integer, Float, Infinity or NaN
+func (json *JSON5) IsNumber() bool {
+	return json.num != nil
+}
+
+// String return number literal as written in the source
+func (num Number) String() string {
+	return num.lit
+}
+
+// Kind return kind of the number, Integer, Float, Infinity or NaN
+func (num Number) Kind() int {
+	return num.kind
+}
+
+// Base return 16 for hexadecimal literal, 10 otherwise
+func (num Number) Base() int {
+	if num.hex {
+		return 16
+	}
+
+	return 10
+}
+
+// HasSign check if the literal begins with '+' or '-'
+func (num Number) HasSign() bool {
+	return num.sign
+}
+
+// IsNegative check if the literal begins with '-'
+func (num Number) IsNegative() bool {
+	return num.neg
+}
+
+// HasExponent check if the literal has exponent, such as 1e3
+func (num Number) HasExponent() bool {
+	return num.exp
+}
+
+// HasLeadingPoint check if the literal has decimal point without digit before it, such as .5
+func (num Number) HasLeadingPoint() bool {
+	return num.leadingPoint
+}
+
+// HasTrailingPoint check if the literal has decimal point without digit after it, such as 5.
+func (num Number) HasTrailingPoint() bool {
+	return num.trailingPoint
+}
+
+// IsNegativeZero check if the literal is a negative zero, such as -0 or -0.0
+func (num Number) IsNegativeZero() bool {
+	return num.negZero
+}
+
+// split return sign and literal without sign
+func (num Number) split() (neg bool, unsigned string) {
+	switch {
+	case strings.HasPrefix(num.lit, "-"):
+		return true, num.lit[1:]
+	case strings.HasPrefix(num.lit, "+"):
+		return false, num.lit[1:]
+	}
+
+	return false, num.lit
+}
+
+// hexDigits return hex digits of hexadecimal literal
+func hexDigits(unsigned string) (string, bool) {
+	if len(unsigned) > 2 && unsigned[0] == '0' && (unsigned[1] == 'x' || unsigned[1] == 'X') {
+		return unsigned[2:], true
+	}
+
+	return "", false
+}
+
+func (num Number) rangeErr(fn string) error {
+	return &strconv.NumError{Func: fn, Num: num.lit, Err: strconv.ErrRange}
+}
+
+func (num Number) syntaxErr(fn string) error {
+	return &strconv.NumError{Func: fn, Num: num.lit, Err: strconv.ErrSyntax}
+}
+
+// BigInt return number as big.Int. Return error wrapping strconv.ErrSyntax if number is not an integer,
+// such as 1.5. Integral float literal such as 1e3 or 2.0 is converted
+func (num Number) BigInt() (*big.Int, error) {
+	switch num.kind {
+	case Infinity:
+		return nil, num.rangeErr("BigInt")
+	case NaN:
+		return nil, num.syntaxErr("BigInt")
+	}
+
+	neg, unsigned := num.split()
+	if digits, ok := hexDigits(unsigned); ok {
+		i, ok := new(big.Int).SetString(digits, 16)
+		if !ok {
+			return nil, num.syntaxErr("BigInt")
+		}
+
+		if neg {
+			i.Neg(i)
+		}
+
+		return i, nil
+	}
+
+	if !strings.ContainsAny(unsigned, ".eE") {
+		i, ok := new(big.Int).SetString(num.lit, 10)
+		if !ok {
+			return nil, num.syntaxErr("BigInt")
+		}
+
+		return i, nil
+	}
+
+	rat, ok := new(big.Rat).SetString(num.lit)
+	if !ok || !rat.IsInt() {
+		return nil, num.syntaxErr("BigInt")
+	}
+
+	return rat.Num(), nil
+}
+
+// BigFloat return number as big.Float, with enough precision to hold every digit of the literal.
+// NaN can not be represented by big.Float, error wrapping strconv.ErrSyntax is returned for it
+func (num Number) BigFloat() (*big.Float, error) {
+	switch num.kind {
+	case Infinity:
+		return new(big.Float).SetInf(num.neg), nil
+	case NaN:
+		return nil, num.syntaxErr("BigFloat")
+	}
+
+	neg, unsigned := num.split()
+	if _, ok := hexDigits(unsigned); ok {
+		i, err := num.BigInt()
+		if err != nil {
+			return nil, err
+		}
+
+		prec := uint(i.BitLen())
+		if prec < 64 {
+			prec = 64
+		}
+
+		return new(big.Float).SetPrec(prec).SetInt(i), nil
+	}
+
+	// a decimal digit takes less than 4 bits
+	prec := uint(4*len(num.lit) + 64)
+	f, _, err := big.ParseFloat(unsigned, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, num.syntaxErr("BigFloat")
+	}
+
+	if neg {
+		f.Neg(f)
+	}
+
+	return f, nil
+}
+
+// Int64 return number as int64. If number overflow int64, Infinity included, the nearest int64 is returned with
+// error wrapping strconv.ErrRange
+func (num Number) Int64() (int64, error) {
+	if num.kind == Infinity {
+		if num.neg {
+			return math.MinInt64, num.rangeErr("Int64")
+		}
+
+		return math.MaxInt64, num.rangeErr("Int64")
+	}
+
+	i, err := num.BigInt()
+	if err != nil {
+		return 0, err
+	}
+
+	if !i.IsInt64() {
+		if i.Sign() < 0 {
+			return math.MinInt64, num.rangeErr("Int64")
+		}
+
+		return math.MaxInt64, num.rangeErr("Int64")
+	}
+
+	return i.Int64(), nil
+}
+
+// Uint64 return number as uint64. If number is negative or overflow uint64, Infinity included, the nearest uint64 is
+// returned with error wrapping strconv.ErrRange
+func (num Number) Uint64() (uint64, error) {
+	if num.kind == Infinity {
+		if num.neg {
+			return 0, num.rangeErr("Uint64")
+		}
+
+		return math.MaxUint64, num.rangeErr("Uint64")
+	}
+
+	i, err := num.BigInt()
+	if err != nil {
+		return 0, err
+	}
+
+	if i.Sign() < 0 {
+		return 0, num.rangeErr("Uint64")
+	}
+
+	if !i.IsUint64() {
+		return math.MaxUint64, num.rangeErr("Uint64")
+	}
+
+	return i.Uint64(), nil
+}
+
+// Float64 return number as float64. If number overflow float64, ±Inf is returned with
+// error wrapping strconv.ErrRange
+func (num Number) Float64() (float64, error) {
+	switch num.kind {
+	case Infinity:
+		if num.neg {
+			return math.Inf(-1), nil
+		}
+
+		return math.Inf(1), nil
+	case NaN:
+		return math.NaN(), nil
+	}
+
+	neg, unsigned := num.split()
+	if _, ok := hexDigits(unsigned); !ok {
+		f, err := strconv.ParseFloat(num.lit, 64)
+		if err != nil {
+			if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+				return f, num.rangeErr("Float64")
+			}
+
+			return 0, num.syntaxErr("Float64")
+		}
+
+		return f, nil
+	}
+
+	bf, err := num.BigFloat()
+	if err != nil {
+		return 0, err
+	}
+
+	f, _ := bf.Float64()
+	if math.IsInf(f, 0) {
+		return f, num.rangeErr("Float64")
+	}
+
+	if neg && f == 0 {
+		f = math.Copysign(0, -1)
+	}
+
+	return f, nil
+}
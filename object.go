@@ -1,13 +1,15 @@
 package json5extract
 
-import (
-	"io"
-	"unicode"
-)
-
-func parseObj(r reader) (*JSON5, error) {
-	obj := &JSON5{kind: Object, val: make(map[string]*JSON5)}
-	state := new(objState)
+import "unicode"
+
+func parseObj(r *reader) (*JSON5, error) {
+	defer r.leave()
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+
+	obj := &JSON5{kind: Object, val: make([]Member, 0)}
+	state := &objState{index: make(map[string]int)}
 	obj.push('{')
 	err := parseKeyVal(r, obj, state)
 	if err != nil {
@@ -21,11 +23,7 @@ func parseObj(r reader) (*JSON5, error) {
 	for {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			if err == io.EOF {
-				return nil, ErrInvalidFormat
-			}
-
-			return nil, err
+			return nil, r.readErr(err, "',' or '}'")
 		}
 
 		if unicode.IsControl(char) || char == ' ' {
@@ -34,7 +32,7 @@ func parseObj(r reader) (*JSON5, error) {
 
 		if char == ',' {
 			if state.onNext {
-				return nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeUnexpectedChar, "key or '}'")
 			}
 
 			obj.push(',')
@@ -57,44 +55,64 @@ func parseObj(r reader) (*JSON5, error) {
 			continue
 		}
 
-		r.UnreadRune()
-		if state.onNext {
-			err := parseKeyVal(r, obj, state)
-			if err != nil {
-				return nil, err
-			}
+		if !state.onNext {
+			return nil, r.syntaxErr(CodeMissingComma, "',' or '}'")
+		}
 
-			if state.isEnd {
-				break
-			}
+		r.UnreadRune()
+		if err := parseKeyVal(r, obj, state); err != nil {
+			return nil, err
+		}
 
-			state.onNext = false
-			continue
+		if state.isEnd {
+			break
 		}
 
-		return nil, ErrInvalidFormat
+		state.onNext = false
+	}
+
+	// dropped duplicate members must not appear in raw
+	if state.hasDup && r.opts.DuplicateKeys != DuplicateKeepAll {
+		rebuildObjRaw(obj)
 	}
 
 	return obj, nil
 }
 
+// rebuild object raw from its members
+func rebuildObjRaw(obj *JSON5) {
+	obj.raw = []rune{'{'}
+	for i, m := range obj.val.([]Member) {
+		if i > 0 {
+			obj.push(',')
+		}
+
+		obj.pushRns(m.Value.key.raw)
+		obj.push(':')
+		obj.pushRns(m.Value.raw)
+	}
+
+	obj.push('}')
+}
+
 type objState struct {
 	isEnd  bool
 	onNext bool
+	// index of members by key
+	index  map[string]int
+	hasDup bool
+	// number of parsed members, dropped duplicates included
+	count int
 }
 
-func parseKeyVal(r reader, obj *JSON5, state *objState) error {
-	keyVal := obj.val.(map[string]*JSON5)
-	var id []rune
+func parseKeyVal(r *reader, obj *JSON5, state *objState) error {
+	members := obj.val.([]Member)
+	var key *JSON5
 	var idRaw []rune
 	for {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			if err == io.EOF {
-				return ErrInvalidFormat
-			}
-
-			return err
+			return r.readErr(err, "key or '}'")
 		}
 
 		if unicode.IsControl(char) || char == ' ' {
@@ -116,13 +134,13 @@ func parseKeyVal(r reader, obj *JSON5, state *objState) error {
 			continue
 		}
 
-		i, iraw, err := parseIdentifier(r, char)
+		k, err := parseIdentifier(r, char)
 		if err != nil {
 			return err
 		}
 
-		id = i
-		idRaw = iraw
+		key = k
+		idRaw = append(idRaw, k.raw...)
 		idRaw = append(idRaw, ':')
 		break
 	}
@@ -131,11 +149,7 @@ func parseKeyVal(r reader, obj *JSON5, state *objState) error {
 	for {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			if err == io.EOF {
-				return ErrInvalidFormat
-			}
-
-			return err
+			return r.readErr(err, "value")
 		}
 
 		if unicode.IsControl(char) || char == ' ' {
@@ -157,15 +171,38 @@ func parseKeyVal(r reader, obj *JSON5, state *objState) error {
 		}
 
 		if val != nil {
-			keyVal[string(id)] = val
+			if err := checkMembers(r, state.count); err != nil {
+				return err
+			}
+
+			state.count++
+			val.key = key
+			name := key.val.(string)
+			if i, ok := state.index[name]; ok {
+				state.hasDup = true
+				switch r.opts.DuplicateKeys {
+				case DuplicateKeepFirst:
+				case DuplicateKeepAll:
+					members = append(members, Member{Key: name, Value: val})
+				case DuplicateReject:
+					return &DuplicateKeyError{Key: name, First: members[i].Value.key.start, Pos: key.start}
+				default:
+					// latter member replace the former one with the same key
+					members[i].Value = val
+				}
+			} else {
+				state.index[name] = len(members)
+				members = append(members, Member{Key: name, Value: val})
+			}
+
 			obj.pushRns(idRaw)
 			obj.pushRns(val.raw)
-			obj.val = keyVal
+			obj.val = members
 
 			break
 		}
 
-		return ErrInvalidFormat
+		return r.syntaxErr(CodeUnexpectedChar, "value")
 	}
 
 	return nil
@@ -0,0 +1,148 @@
+package json5extract
+
+// Duplicate key policies, decide which member is kept when a key repeats in an object
+const (
+	// DuplicateKeepLast keep the value of the last member at the position of the first one
+	DuplicateKeepLast = iota
+	// DuplicateKeepFirst keep the first member
+	DuplicateKeepFirst
+	// DuplicateKeepAll keep every member, see JSON5.Members
+	DuplicateKeepAll
+	// DuplicateReject reject the object with DuplicateKeyError
+	DuplicateReject
+)
+
+// Limits bound resources used to parse a single extracted value. Zero field means no limit.
+// A value exceeding a limit is skipped with LimitError
+type Limits struct {
+	// MaxDepth is the maximum nesting depth of arrays and objects being parsed, a deeper
+	// value is not parsed at all. See Options.MaxNesting to filter extracted values
+	MaxDepth int
+	// MaxStringLength is the maximum number of runes in a string, quotes included
+	MaxStringLength int
+	// MaxMembers is the maximum number of elements of an array or members of an object
+	MaxMembers int
+	// MaxNodes is the maximum number of values in an extracted value, itself included
+	MaxNodes int
+	// MaxValueBytes is the maximum number of input bytes of an extracted value
+	MaxValueBytes int
+}
+
+// Options restrict which values are extracted. The zero value extract every value.
+// When an array or object is rejected, values nested inside it are still considered
+type Options struct {
+	// Kinds restrict extracted values to these kinds. Empty means every kind
+	Kinds []int
+	// MinLength is the minimum length of a value raw runes
+	MinLength int
+	// MinMembers is the minimum number of array elements or object members.
+	// Kinds other than Array and Object have no member
+	MinMembers int
+	// MinNesting is the minimum nesting depth of an extracted value. A value which is not
+	// array or object has nesting 0, [] has nesting 1 and [[]] has nesting 2
+	MinNesting int
+	// MaxNesting is the maximum nesting depth of an extracted value, 0 means no maximum.
+	// Unlike Limits.MaxDepth, a deeper value is still parsed and only values nested
+	// inside it are extracted
+	MaxNesting int
+	// WordBoundary reject number, boolean and null which are preceded or followed by
+	// an identifier char, so "untrue", "nullify" or "abc123def" yield nothing. A number
+	// is also terminated by any char other than identifier char and decimal point
+	WordBoundary bool
+	// DuplicateKeys is the duplicate key policy, default to DuplicateKeepLast.
+	// Except with DuplicateKeepAll, dropped members are removed from raw
+	DuplicateKeys int
+	// Diagnostics, if not nil, is called for every candidate which looked like a value
+	// but could not be parsed
+	Diagnostics func(Diagnostic)
+	// Limits bound resources used to parse a value
+	Limits Limits
+	// RejectNumberOverflow reject integers which do not fit in int64 and floats which
+	// overflow float64, instead of keeping a clamped value. JSON5.Number always keep
+	// the exact literal
+	RejectNumberOverflow bool
+}
+
+// return the first non nil options, or default options
+func optionsOf(opts []*Options) *Options {
+	for _, o := range opts {
+		if o != nil {
+			return o
+		}
+	}
+
+	return new(Options)
+}
+
+// accept check whether json satisfies options
+func (opts *Options) accept(json *JSON5) bool {
+	if len(opts.Kinds) > 0 {
+		found := false
+		for _, k := range opts.Kinds {
+			if k == json.kind {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if len(json.raw) < opts.MinLength {
+		return false
+	}
+
+	if opts.MinMembers > 0 && memberCount(json) < opts.MinMembers {
+		return false
+	}
+
+	if opts.MinNesting > 0 || opts.MaxNesting > 0 {
+		d := depth(json)
+		if d < opts.MinNesting {
+			return false
+		}
+
+		if opts.MaxNesting > 0 && d > opts.MaxNesting {
+			return false
+		}
+	}
+
+	return true
+}
+
+func memberCount(json *JSON5) int {
+	switch json.kind {
+	case Array:
+		return len(json.Array())
+	case Object:
+		return len(json.Members())
+	}
+
+	return 0
+}
+
+// depth return nesting depth of json
+func depth(json *JSON5) int {
+	var vals []*JSON5
+	switch json.kind {
+	case Array:
+		vals = json.Array()
+	case Object:
+		for _, m := range json.Members() {
+			vals = append(vals, m.Value)
+		}
+	default:
+		return 0
+	}
+
+	max := 0
+	for _, val := range vals {
+		if d := depth(val); d > max {
+			max = d
+		}
+	}
+
+	return max + 1
+}
@@ -0,0 +1,97 @@
+package json5extract
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		limit  string
+		limits Limits
+		in     string
+		// values extracted once the exceeding candidate is skipped
+		want []string
+	}{
+		{"MaxDepth", Limits{MaxDepth: 2}, `[[[1]]]`, []string{`[[1]]`}},
+		{"MaxStringLength", Limits{MaxStringLength: 5}, `"abcdef"`, []string{}},
+		{"MaxMembers", Limits{MaxMembers: 2}, `{"a": [1, 2, 3]}`, []string{`"a"`, `1`, `2`, `3`}},
+		{"MaxNodes", Limits{MaxNodes: 3}, `[1, [2, 3]]`, []string{`1`, `[2,3]`}},
+		{"MaxValueBytes", Limits{MaxValueBytes: 6}, `[1, 2, 3]`, []string{`1`, `2`, `3`}},
+	}
+
+	for _, test := range tests {
+		var limitErr *LimitError
+		opts := &Options{
+			Limits: test.limits,
+			Diagnostics: func(d Diagnostic) {
+				if limitErr == nil {
+					errors.As(d.Err, &limitErr)
+				}
+			},
+		}
+
+		vals, err := FromString(test.in, opts)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.limit, err)
+			continue
+		}
+
+		if limitErr == nil || limitErr.Limit != test.limit || !errors.Is(limitErr, ErrLimitExceeded) {
+			t.Errorf("%s: got diagnostic error %v, want LimitError of %s", test.limit, limitErr, test.limit)
+		}
+
+		if got := compact(vals); !equalStrs(got, test.want) {
+			t.Errorf("%s: got %q, want %q", test.limit, got, test.want)
+		}
+
+		// Parse fail with the limit error
+		_, err = Parse([]byte(test.in), opts)
+		if !errors.As(err, &limitErr) || limitErr.Limit != test.limit {
+			t.Errorf("%s: Parse returned %v, want LimitError of %s", test.limit, err, test.limit)
+		}
+	}
+}
+
+func TestNestingFilter(t *testing.T) {
+	// values inside an accepted value are not extracted again
+	in := `1 [] [[2]] {"a": [[3]]}`
+	tests := []struct {
+		opts *Options
+		want []string
+	}{
+		{&Options{MinNesting: 2}, []string{`[[2]]`, `{"a":[[3]]}`}},
+		{&Options{MaxNesting: 1}, []string{`1`, `[]`, `[2]`, `"a"`, `[3]`}},
+		{&Options{MinNesting: 1, MaxNesting: 2}, []string{`[]`, `[[2]]`, `[[3]]`}},
+	}
+
+	for _, test := range tests {
+		vals, err := FromString(in, test.opts)
+		if err != nil {
+			t.Errorf("%+v: unexpected error %v", test.opts, err)
+			continue
+		}
+
+		if got := compact(vals); !equalStrs(got, test.want) {
+			t.Errorf("%+v: got %q, want %q", test.opts, got, test.want)
+		}
+	}
+}
+
+func TestMaxValueBytesFromValueStart(t *testing.T) {
+	// whitespaces and comments around the value are not counted
+	opts := &Options{Limits: Limits{MaxValueBytes: 5}}
+	for _, in := range []string{"          [1]", "/* comment */ [1] // end\n", "\n\n'abc'\n\n"} {
+		if _, err := Parse([]byte(in), opts); err != nil {
+			t.Errorf("%q: Parse returned %v", in, err)
+		}
+
+		if _, err := ParseDocument([]byte(in), opts); err != nil {
+			t.Errorf("%q: ParseDocument returned %v", in, err)
+		}
+	}
+
+	if _, err := Parse([]byte("  [1, 2]"), opts); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("got %v, want ErrLimitExceeded", err)
+	}
+}
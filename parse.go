@@ -1,7 +1,9 @@
 package json5extract
 
 import (
+	"bytes"
 	"io"
+	"unicode"
 )
 
 // JSON5 kinds
@@ -20,9 +22,21 @@ const (
 // JSON5 represent parsed value of JSON5 types. Check JSON5.Kind to know
 // which data type a value is
 type JSON5 struct {
-	kind int
-	val  interface{}
-	raw  []rune
+	kind  int
+	val   interface{}
+	raw   []rune
+	start Position
+	end   Position
+	key   *JSON5
+	// number literal of numeric kinds
+	num *Number
+	// array or object containing the value, index is position in parent array
+	parent *JSON5
+	index  int
+	// comments attached to the value, nil if none
+	comments *nodeComments
+	// exact source text of the value, nil if not parsed
+	src []byte
 }
 
 // Kind return json kind
@@ -93,25 +107,88 @@ func (json *JSON5) Array() []*JSON5 {
 	return json.val.([]*JSON5)
 }
 
+// Member is an object member. The key position is available from Value.Key()
+type Member struct {
+	Key   string
+	Value *JSON5
+}
+
 // Object return map of JSON5 values. Will panic if kind is not Object
 func (json *JSON5) Object() map[string]*JSON5 {
+	members := json.Members()
+	obj := make(map[string]*JSON5, len(members))
+	for _, m := range members {
+		obj[m.Key] = m.Value
+	}
+
+	return obj
+}
+
+// Members return object members in the order they appear in the source.
+// Will panic if kind is not Object
+func (json *JSON5) Members() []Member {
 	if json.kind != Object {
 		panic("value is not object")
 	}
 
-	return json.val.(map[string]*JSON5)
+	return json.val.([]Member)
 }
 
-// Bytes return parsed raw bytes of JSON5
+// Get return value of object member with key, the last one if there are several.
+// Will panic if kind is not Object
+func (json *JSON5) Get(key string) (*JSON5, bool) {
+	members := json.Members()
+	for i := len(members) - 1; i >= 0; i-- {
+		if members[i].Key == key {
+			return members[i].Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Bytes return compact raw bytes of JSON5, see Compact
 func (json *JSON5) Bytes() []byte {
+	return json.Compact()
+}
+
+// Compact return raw bytes of JSON5 with whitespaces and comments inside the value dropped,
+// such as {a:1} for { a : 1 /*x*/ }. See Original for the text found in the source
+func (json *JSON5) Compact() []byte {
 	return runesToUTF8(json.raw)
 }
 
+// Original return the exact source text of the value, whitespaces and comments inside it included.
+// Return nil if the value was not parsed from a source
+func (json *JSON5) Original() []byte {
+	if json.src == nil {
+		return nil
+	}
+
+	return append([]byte(nil), json.src...)
+}
+
 // Runes return parsed raw bytes of JSON5
 func (json *JSON5) Runes() []rune {
 	return json.raw
 }
 
+// Start return position of the first char of the value in the source
+func (json *JSON5) Start() Position {
+	return json.start
+}
+
+// End return position right after the last char of the value in the source
+func (json *JSON5) End() Position {
+	return json.end
+}
+
+// Key return the key of an object member as String value, positioned at the key in the source.
+// Return nil if value is not an object member
+func (json *JSON5) Key() *JSON5 {
+	return json.key
+}
+
 func (json *JSON5) push(char rune) {
 	json.raw = append(json.raw, char)
 }
@@ -120,152 +197,169 @@ func (json *JSON5) pushRns(chars []rune) {
 	json.raw = append(json.raw, chars...)
 }
 
-func parseAll(r reader) ([]*JSON5, error) {
+func parseAll(ext *Extractor) ([]*JSON5, error) {
 	json5s := make([]*JSON5, 0)
 	for {
-		char, _, err := r.ReadRune()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-
-			return nil, err
-		}
-
-		json5, err := parse(r, char)
+		json5, err := ext.Next()
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
 
-			continue
+			return json5s, err
 		}
 
-		if json5 != nil {
-			json5s = append(json5s, json5)
-		}
+		json5s = append(json5s, json5)
 	}
 
 	return json5s, nil
 }
 
-func parse(r reader, char rune) (*JSON5, error) {
-	// parse double quoted string
-	if char == '"' {
-		json5, err := parseStr(r, doubleQuotedStr)
+// Parse parse data as a single JSON5 document, which is one value surrounded by optional
+// whitespaces and comments. Only the first non nil opts is used, options restricting
+// extracted values are ignored
+func Parse(data []byte, opts ...*Options) (*JSON5, error) {
+	r := newReader(bytes.NewReader(data))
+	r.opts = optionsOf(opts)
+
+	return parseDoc(r)
+}
+
+func parseDoc(r *reader) (*JSON5, error) {
+	var json5 *JSON5
+	for {
+		char, _, err := r.ReadRune()
 		if err != nil {
-			if err == io.EOF {
-				return nil, err
+			if err == io.EOF && json5 != nil {
+				setSource(json5, r.slice(json5.start, json5.end))
+				attachComments(json5, r.comments)
+				return json5, nil
 			}
 
-			r.UnreadRune()
-			return nil, err
+			return nil, r.readErr(err, "value")
 		}
 
-		return json5, nil
-	}
+		if unicode.IsControl(char) || char == ' ' {
+			continue
+		}
 
-	// parse single quoted string
-	if char == '\'' {
-		json5, err := parseStr(r, singleQuotedStr)
-		if err != nil {
-			if err == io.EOF {
+		// comment
+		if char == '/' {
+			if _, err := parseComment(r); err != nil {
 				return nil, err
 			}
 
-			r.UnreadRune()
-			return nil, err
+			continue
 		}
 
-		return json5, nil
-	}
+		if json5 != nil {
+			return nil, r.syntaxErr(CodeTrailingGarbage, "end of input")
+		}
 
-	// parse number
-	if isCharNumBegin(char) {
-		json5, err := parseNum(r, char)
+		json5, err = parse(r, char)
 		if err != nil {
-			if err == io.EOF {
-				return nil, err
-			}
-
-			r.UnreadRune()
 			return nil, err
 		}
 
-		return json5, nil
+		if json5 == nil {
+			return nil, r.syntaxErr(CodeUnexpectedChar, "value")
+		}
 	}
+}
 
-	// parse true boolean
-	if char == 't' {
-		json5, err := parseTrueBool(r)
-		if err != nil {
-			if err == io.EOF {
-				return nil, err
-			}
-
-			r.UnreadRune()
-			return nil, err
+// setSource set exact source text of json and its descendants, src is the source text of json
+func setSource(json *JSON5, src []byte) {
+	var set func(v *JSON5)
+	set = func(v *JSON5) {
+		from := v.start.Offset - json.start.Offset
+		v.src = src[from : from+v.end.Offset-v.start.Offset : from+v.end.Offset-v.start.Offset]
+		if v.key != nil {
+			set(v.key)
 		}
 
-		return json5, nil
+		switch v.kind {
+		case Array:
+			for _, e := range v.Array() {
+				set(e)
+			}
+		case Object:
+			for _, m := range v.Members() {
+				set(m.Value)
+			}
+		}
 	}
 
-	// parse false boolean
-	if char == 'f' {
-		json5, err := parseFalseBool(r)
-		if err != nil {
-			if err == io.EOF {
-				return nil, err
-			}
+	set(json)
+}
 
-			r.UnreadRune()
-			return nil, err
-		}
+// parse parse a value beginning with char, which is the last rune read from r,
+// and record the value position
+func parse(r *reader, char rune) (*JSON5, error) {
+	r.nodes++
+	if max := r.opts.Limits.MaxNodes; max > 0 && r.nodes > max {
+		return nil, r.limitErr("MaxNodes", max)
+	}
 
-		return json5, nil
+	start := r.lastPos()
+	if f, ok := r.failed[start.Offset]; ok {
+		r.seek(f.reached)
+		return nil, f.err
 	}
 
-	// parse null
-	if char == 'n' {
-		json5, err := parseNull(r)
-		if err != nil {
-			if err == io.EOF {
-				return nil, err
-			}
+	// value bytes are counted from the beginning of the outermost value
+	if r.valStart < 0 {
+		r.valStart = start.Offset
+		defer func() { r.valStart = -1 }()
+	}
 
-			r.UnreadRune()
-			return nil, err
-		}
+	json5, err := parseValue(r, char)
+	if err != nil {
+		r.fail(start, err)
+		return nil, err
+	}
 
-		return json5, nil
+	if json5 != nil {
+		json5.start = start
+		json5.end = r.pos()
+		json5.adopt()
 	}
 
-	// parse array
-	if char == '[' {
-		json5, err := parseArray(r)
-		if err != nil {
-			if err == io.EOF {
-				return nil, err
-			}
+	return json5, nil
+}
 
-			r.UnreadRune()
-			return nil, err
-		}
-		return json5, nil
-	}
+func parseValue(r *reader, char rune) (*JSON5, error) {
+	switch {
+	// parse double quoted string
+	case char == '"':
+		return parseStr(r, doubleQuotedStr)
 
-	// parse object
-	if char == '{' {
-		json5, err := parseObj(r)
-		if err != nil {
-			if err == io.EOF {
-				return nil, err
-			}
+	// parse single quoted string
+	case char == '\'':
+		return parseStr(r, singleQuotedStr)
 
-			r.UnreadRune()
-			return nil, err
-		}
-		return json5, nil
+	// parse number
+	case isCharNumBegin(char):
+		return parseNum(r, char)
+
+	// parse true boolean
+	case char == 't':
+		return parseTrueBool(r)
+
+	// parse false boolean
+	case char == 'f':
+		return parseFalseBool(r)
+
+	// parse null
+	case char == 'n':
+		return parseNull(r)
+
+	// parse array
+	case char == '[':
+		return parseArray(r)
+
+	// parse object
+	case char == '{':
+		return parseObj(r)
 	}
 
 	return nil, nil
@@ -0,0 +1,222 @@
+package json5extract
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Segment is a step of Path, either an object member key or an array element index
+type Segment struct {
+	Key string
+	// Index is array element index, -1 for object member
+	Index int
+}
+
+// IsIndex check if segment is an array element index
+func (seg Segment) IsIndex() bool {
+	return seg.Index >= 0
+}
+
+// Path locate a value from the root of parsed value
+type Path []Segment
+
+// Pointer return path as JSON Pointer (RFC 6901), such as /a/3/b. Root path is empty string
+func (p Path) Pointer() string {
+	var b strings.Builder
+	for _, seg := range p {
+		b.WriteByte('/')
+		if seg.IsIndex() {
+			b.WriteString(strconv.Itoa(seg.Index))
+			continue
+		}
+
+		b.WriteString(escapeToken(seg.Key))
+	}
+
+	return b.String()
+}
+
+// String return path as JSON Pointer, see Path.Pointer
+func (p Path) String() string {
+	return p.Pointer()
+}
+
+// PointerError occured when a JSON Pointer can not be resolved
+type PointerError struct {
+	// Pointer is the pointer being resolved
+	Pointer string
+	// Resolved is the longest prefix of Pointer which was resolved
+	Resolved string
+	// Kind and Pos are kind and position of the value at Resolved
+	Kind int
+	Pos  Position
+	// Msg describe why the next reference token can not be resolved
+	Msg string
+}
+
+func (err *PointerError) Error() string {
+	resolved := err.Resolved
+	if resolved == "" {
+		resolved = "(root)"
+	}
+
+	return fmt.Sprintf("JSON pointer %q: %s at %s (line %d, column %d)",
+		err.Pointer, err.Msg, resolved, err.Pos.Line, err.Pos.Column)
+}
+
+// Is report whether target is ErrNotFound
+func (err *PointerError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// Parent return array or object containing the value, nil for the root of parsed value
+func (json *JSON5) Parent() *JSON5 {
+	return json.parent
+}
+
+// Path return path of the value from the root of parsed value, which is the value returned
+// by Parse or Extractor.Next
+func (json *JSON5) Path() Path {
+	var p Path
+	for v := json; v.parent != nil; v = v.parent {
+		if v.parent.kind == Array {
+			p = append(p, Segment{Index: v.index})
+		} else {
+			p = append(p, Segment{Key: v.key.val.(string), Index: -1})
+		}
+	}
+
+	// reverse, segments were collected from the value up to the root
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+
+	return p
+}
+
+// Pointer return value located by JSON Pointer (RFC 6901) ptr, relative to this value.
+// Empty ptr is the value itself. Return PointerError if a key is missing, an index is out of
+// range or a token traverses a value which is neither array nor object. The last member wins
+// if a key repeats
+func (json *JSON5) Pointer(ptr string) (*JSON5, error) {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := json
+	for i, token := range tokens {
+		var msg string
+		switch cur.kind {
+		case Object:
+			if v, ok := cur.Get(token); ok {
+				cur = v
+				continue
+			}
+
+			msg = fmt.Sprintf("key %q not found in object", token)
+		case Array:
+			idx, ok := arrayIndex(token)
+			if ok && idx < len(cur.Array()) {
+				cur = cur.Array()[idx]
+				continue
+			}
+
+			switch {
+			case token == "-":
+				msg = "\"-\" refers past the last array element"
+			case !ok:
+				msg = fmt.Sprintf("%q is not an array index", token)
+			default:
+				msg = fmt.Sprintf("index %s out of range of array of length %d", token, len(cur.Array()))
+			}
+		default:
+			msg = fmt.Sprintf("cannot look up %q in %s", token, kindNames[cur.kind])
+		}
+
+		return nil, &PointerError{
+			Pointer:  ptr,
+			Resolved: joinTokens(tokens[:i]),
+			Kind:     cur.kind,
+			Pos:      cur.start,
+			Msg:      msg,
+		}
+	}
+
+	return cur, nil
+}
+
+// joinTokens return JSON Pointer of reference tokens
+func joinTokens(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(escapeToken(token))
+	}
+
+	return b.String()
+}
+
+// parsePointer split JSON Pointer into unescaped reference tokens
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+
+	if ptr[0] != '/' {
+		return nil, fmt.Errorf("json5extract: invalid JSON pointer %q, must be empty or begin with '/'", ptr)
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("json5extract: invalid JSON pointer %q, '~' must be followed by '0' or '1'", ptr)
+			}
+		}
+
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func escapeToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// arrayIndex parse array index token, which is 0 or decimal digits without leading zero
+func arrayIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+	}
+
+	idx, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, false
+	}
+
+	return idx, true
+}
+
+// adopt set parent links of array elements and object member values
+func (json *JSON5) adopt() {
+	switch json.kind {
+	case Array:
+		for i, v := range json.Array() {
+			v.parent = json
+			v.index = i
+		}
+	case Object:
+		for _, m := range json.Members() {
+			m.Value.parent = json
+		}
+	}
+}
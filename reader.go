@@ -1,29 +1,304 @@
 package json5extract
 
 import (
-	"bufio"
-	"bytes"
+	"context"
+	"errors"
 	"io"
-	"strings"
+	"unicode/utf8"
 )
 
-type reader interface {
-	ReadRune() (r rune, size int, err error)
-	UnreadRune() error
+// Position describe a location in the source input. Lines are separated by '\n'
+type Position struct {
+	// Offset is the byte offset, starting at 0
+	Offset int
+	// RuneOffset is the rune offset, starting at 0
+	RuneOffset int
+	// Line is the line number, starting at 1
+	Line int
+	// Column is the rune column in line, starting at 1
+	Column int
+	// ByteColumn is the byte column in line, starting at 1
+	ByteColumn int
 }
 
-func readFromBytes(byts []byte) reader {
-	buff := bufio.NewReader(bytes.NewReader(byts))
-	return buff
+var startPos = Position{Line: 1, Column: 1, ByteColumn: 1}
+
+// advance return position after rune char with size in bytes
+func (p Position) advance(char rune, size int) Position {
+	p.Offset += size
+	p.RuneOffset++
+	if char == '\n' {
+		p.Line++
+		p.Column = 1
+		p.ByteColumn = 1
+	} else {
+		p.Column++
+		p.ByteColumn += size
+	}
+
+	return p
+}
+
+var errUnreadRune = errors.New("json5extract: invalid use of UnreadRune")
+
+// size of chunk read from the source at once
+const readChunkSize = 4096
+
+// number of runes read between context cancellation checks
+const ctxCheckInterval = 1024
+
+// reader read runes from source while tracking position of every rune. Every input
+// since the last call to release is kept in buffer, so reader can seek back to any
+// position after it
+type reader struct {
+	src io.Reader
+	// buffered input, buf[0] is located at base
+	buf  []byte
+	base Position
+	// index of the next byte to be read in buf
+	i    int
+	cur  Position
+	prev Position
+	// last rune read
+	last      rune
+	canUnread bool
+	// error returned by src, io.EOF included
+	srcErr error
+	ctx    context.Context
+	// runes read since the last context check
+	reads  int
+	ctxErr error
+	opts   *Options
+	// nesting depth of the value being parsed
+	depth int
+	// number of values parsed since the last release
+	nodes int
+	// comments read since the last release
+	comments []Comment
+	// failures of values parsed since the last release, by offset of their first char.
+	// Parsing a value depend only on input after it, so a failed value is not parsed again
+	failed map[int]failure
+	// largest offset in failed
+	failedMax int
+	// whether a limit was exceeded since the last release, failures are then not recorded
+	limited bool
+	// offset of the outermost value being parsed, -1 outside of a value
+	valStart int
+}
+
+// failure is the result of a value which could not be parsed
+type failure struct {
+	err error
+	// reached is position where parsing stopped
+	reached Position
+}
+
+func newReader(src io.Reader) *reader {
+	return &reader{src: src, base: startPos, cur: startPos, opts: new(Options), valStart: -1}
+}
+
+// checkContext return error of reader context if it is done
+func (r *reader) checkContext() error {
+	if r.ctxErr != nil {
+		return r.ctxErr
+	}
+
+	if r.ctx == nil {
+		return nil
+	}
+
+	r.reads = 0
+	r.ctxErr = r.ctx.Err()
+
+	return r.ctxErr
+}
+
+// fill read from source until buf contains a full rune after r.i or source is exhausted
+func (r *reader) fill() {
+	for r.srcErr == nil && !utf8.FullRune(r.buf[r.i:]) {
+		if cap(r.buf)-len(r.buf) < readChunkSize {
+			buf := make([]byte, len(r.buf), 2*cap(r.buf)+readChunkSize)
+			copy(buf, r.buf)
+			r.buf = buf
+		}
+
+		n, err := r.src.Read(r.buf[len(r.buf):cap(r.buf)])
+		r.buf = r.buf[:len(r.buf)+n]
+		if err != nil {
+			r.srcErr = err
+		}
+	}
+}
+
+// limitErr return LimitError at the last rune read
+func (r *reader) limitErr(limit string, max int) error {
+	r.limited = true
+	return &LimitError{Limit: limit, Max: max, Pos: r.prev}
+}
+
+// enter check context and depth limit before parsing array or object, leave must be
+// called once parsing is done
+func (r *reader) enter() error {
+	r.depth++
+	if err := r.checkContext(); err != nil {
+		return err
+	}
+
+	if max := r.opts.Limits.MaxDepth; max > 0 && r.depth > max {
+		return r.limitErr("MaxDepth", max)
+	}
+
+	return nil
+}
+
+func (r *reader) leave() {
+	r.depth--
+}
+
+// ReadRune read a single rune and advance current position
+func (r *reader) ReadRune() (rune, int, error) {
+	r.reads++
+	if r.reads >= ctxCheckInterval || r.ctxErr != nil {
+		if err := r.checkContext(); err != nil {
+			r.canUnread = false
+			return 0, 0, err
+		}
+	}
+
+	r.fill()
+	if r.i == len(r.buf) {
+		r.canUnread = false
+		return 0, 0, r.srcErr
+	}
+
+	char, size := utf8.DecodeRune(r.buf[r.i:])
+	if max := r.opts.Limits.MaxValueBytes; max > 0 && r.valStart >= 0 && r.cur.Offset+size-r.valStart > max {
+		r.canUnread = false
+		r.limited = true
+		return 0, 0, &LimitError{Limit: "MaxValueBytes", Max: max, Pos: r.cur}
+	}
+
+	r.i += size
+	r.prev = r.cur
+	r.last = char
+	r.cur = r.cur.advance(char, size)
+	r.canUnread = true
+
+	return char, size, nil
+}
+
+// UnreadRune unread the last rune read by ReadRune
+func (r *reader) UnreadRune() error {
+	if !r.canUnread {
+		return errUnreadRune
+	}
+
+	r.seek(r.prev)
+
+	return nil
+}
+
+// pos return position of the next rune to be read
+func (r *reader) pos() Position {
+	return r.cur
 }
 
-func readFromString(str string) reader {
-	rdr := bufio.NewReader(strings.NewReader(str))
-	return rdr
+// lastPos return position of the last rune read
+func (r *reader) lastPos() Position {
+	return r.prev
 }
 
-func readFromReader(r io.Reader) (reader, error) {
-	buff := bufio.NewReader(r)
+// seek move reader to pos, which must not be before the last release
+func (r *reader) seek(pos Position) {
+	r.i = pos.Offset - r.base.Offset
+	r.cur = pos
+	r.canUnread = false
+}
+
+// syntaxErr return SyntaxError with code at the last rune read
+func (r *reader) syntaxErr(code int, expected string) error {
+	return newSyntaxError(code, r.prev, r.last, expected)
+}
+
+// readErr convert io.EOF returned by ReadRune to SyntaxError, other errors are returned as is
+func (r *reader) readErr(err error, expected string) error {
+	if err == io.EOF {
+		return newSyntaxError(CodeUnexpectedEOF, r.cur, EOFChar, expected)
+	}
+
+	return err
+}
+
+// atWordBoundary check if the next rune is not an identifier char, without consuming it
+func (r *reader) atWordBoundary() bool {
+	pos := r.pos()
+	char, _, err := r.ReadRune()
+	if err != nil {
+		return true
+	}
+
+	r.seek(pos)
+
+	return !isCharIDValid(char, false)
+}
+
+// release discard buffered input before current position, reader can not seek
+// before current position afterward. Limits other than MaxValueBytes are counted from release
+func (r *reader) release() {
+	n := copy(r.buf, r.buf[r.i:])
+	r.buf = r.buf[:n]
+	r.i = 0
+	r.base = r.cur
+	r.nodes = 0
+	r.comments = nil
+	r.limited = false
+	if r.failed != nil && r.failedMax < r.base.Offset {
+		r.failed = nil
+	}
+}
+
+// fail record that value beginning at start failed with err at current position. Failures caused
+// by limits, which are counted from release, or by the source or context are not recorded
+func (r *reader) fail(start Position, err error) {
+	if r.limited || r.err() != nil {
+		return
+	}
+
+	if r.failed == nil {
+		r.failed = make(map[int]failure)
+	}
+
+	r.failed[start.Offset] = failure{err: err, reached: r.pos()}
+	if start.Offset > r.failedMax {
+		r.failedMax = start.Offset
+	}
+}
+
+// slice return copy of input between start and end, which must be after the last release
+func (r *reader) slice(start, end Position) []byte {
+	return append([]byte(nil), r.buf[start.Offset-r.base.Offset:end.Offset-r.base.Offset]...)
+}
+
+// addComment record comment of kind between start and end, which must be after the last release
+func (r *reader) addComment(kind int, start, end Position) {
+	raw := string(r.slice(start, end))
+	text := raw[2:]
+	if kind == CommentBlock {
+		text = raw[2 : len(raw)-2]
+	}
+
+	r.comments = append(r.comments, Comment{Kind: kind, Text: text, Start: start, End: end})
+}
+
+// err return error of reader context or error from source other than io.EOF
+func (r *reader) err() error {
+	if r.ctxErr != nil {
+		return r.ctxErr
+	}
+
+	if r.srcErr == io.EOF {
+		return nil
+	}
 
-	return buff, nil
+	return r.srcErr
 }
@@ -2,17 +2,18 @@ package json5extract
 
 import "os"
 
-// SaveToPath save extracted JSONs to a file path
-func SaveToPath(data []*JSON5, path string) error {
-	return save(data, path)
+// SaveToPath save extracted JSONs to a file path as an array. If opts is given, the array
+// is formatted by the first non nil opts, see JSON5.Format
+func SaveToPath(data []*JSON5, path string, opts ...*FormatOptions) error {
+	return save(data, path, opts)
 }
 
-// Save save extracted JSONs to ./extracted_jsons.json5
-func Save(data []*JSON5) error {
-	return save(data, "./extracted_jsons.json5")
+// Save save extracted JSONs to ./extracted_jsons.json5, see SaveToPath
+func Save(data []*JSON5, opts ...*FormatOptions) error {
+	return save(data, "./extracted_jsons.json5", opts)
 }
 
-func save(data []*JSON5, path string) error {
+func save(data []*JSON5, path string, opts []*FormatOptions) error {
 	f, err := os.Create(path)
 	if err != nil {
 		return err
@@ -20,6 +21,15 @@ func save(data []*JSON5, path string) error {
 
 	defer f.Close()
 
+	if len(opts) > 0 {
+		arr := &JSON5{kind: Array, val: data}
+		if _, err = f.Write(arr.Format(opts...)); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
 	rest := make([]byte, 0)
 	rest = append(rest, 91)
 	c := len(data)
@@ -3,8 +3,10 @@ package json5extract
 import (
 	"bufio"
 	"bytes"
+	"io"
 	"strconv"
 	"unicode"
+	"unicode/utf16"
 )
 
 var (
@@ -17,7 +19,7 @@ const (
 	singleQuotedStr
 )
 
-func parseStr(r reader, ty int) (*JSON5, error) {
+func parseStr(r *reader, ty int) (*JSON5, error) {
 	str := &JSON5{kind: String}
 	if ty == doubleQuotedStr {
 		str.push('"')
@@ -25,10 +27,15 @@ func parseStr(r reader, ty int) (*JSON5, error) {
 		str.push('\'')
 	}
 
+	maxLen := r.opts.Limits.MaxStringLength
 	for {
+		if maxLen > 0 && len(str.raw) > maxLen {
+			return nil, r.limitErr("MaxStringLength", maxLen)
+		}
+
 		char, _, err := r.ReadRune()
 		if err != nil {
-			return nil, err
+			return nil, strReadErr(r, err, ty)
 		}
 
 		// detect escaped char
@@ -37,7 +44,7 @@ func parseStr(r reader, ty int) (*JSON5, error) {
 
 			char, _, err := r.ReadRune()
 			if err != nil {
-				return nil, err
+				return nil, strReadErr(r, err, ty)
 			}
 
 			// unicode
@@ -46,11 +53,11 @@ func parseStr(r reader, ty int) (*JSON5, error) {
 				for i := 0; i < 4; i++ {
 					char, _, err := r.ReadRune()
 					if err != nil {
-						return nil, err
+						return nil, strReadErr(r, err, ty)
 					}
 
 					if !isCharHex(char) {
-						return nil, ErrInvalidFormat
+						return nil, r.syntaxErr(CodeBadEscape, "hex digit")
 					}
 
 					str.push(char)
@@ -65,11 +72,11 @@ func parseStr(r reader, ty int) (*JSON5, error) {
 				for i := 0; i < 2; i++ {
 					char, _, err := r.ReadRune()
 					if err != nil {
-						return nil, err
+						return nil, strReadErr(r, err, ty)
 					}
 
 					if !isCharHex(char) {
-						return nil, ErrInvalidFormat
+						return nil, r.syntaxErr(CodeBadEscape, "hex digit")
 					}
 
 					str.push(char)
@@ -80,34 +87,19 @@ func parseStr(r reader, ty int) (*JSON5, error) {
 
 			// numeric
 			if unicode.IsNumber(char) && char != '0' {
-				return nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeBadEscape, "escape char other than digit")
 			}
 
 			str.push(char)
 			continue
 		}
 
-		// detect line terminator (line feed or return carriage and line feed)
+		// detect line terminator (line feed), which must end an escaped return carriage. Escapes
+		// are read above and unescaped return carriage is rejected below, so any return carriage
+		// before is escaped
 		if char == '\n' {
-			rawlen := len(str.raw)
-			prev1char := str.raw[rawlen-1]
-			if prev1char == '\\' {
-				prev2char := str.raw[rawlen-2]
-				if prev2char == '\\' {
-					return nil, ErrInvalidFormat
-				}
-			}
-
-			if prev1char == '\r' {
-				prev2char := str.raw[rawlen-2]
-				if prev2char != '\\' {
-					return nil, ErrInvalidFormat
-				}
-
-				prev3char := str.raw[rawlen-3]
-				if prev3char == '\\' {
-					return nil, ErrInvalidFormat
-				}
+			if str.raw[len(str.raw)-1] != '\r' {
+				return nil, r.syntaxErr(CodeUnterminatedString, "escaped line terminator")
 			}
 
 			str.push(char)
@@ -119,12 +111,12 @@ func parseStr(r reader, ty int) (*JSON5, error) {
 			rawlen := len(str.raw)
 			prev1char := str.raw[rawlen-1]
 			if prev1char != '\\' {
-				return nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeUnterminatedString, "escaped line terminator")
 			}
 
 			prev2char := str.raw[rawlen-2]
 			if prev2char == '\\' {
-				return nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeUnterminatedString, "escaped line terminator")
 			}
 
 			str.push(char)
@@ -136,12 +128,12 @@ func parseStr(r reader, ty int) (*JSON5, error) {
 			rawlen := len(str.raw)
 			prev1char := str.raw[rawlen-1]
 			if prev1char != '\\' {
-				return nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeUnterminatedString, "escaped line terminator")
 			}
 
 			prev2char := str.raw[rawlen-2]
 			if prev2char == '\\' {
-				return nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeUnterminatedString, "escaped line terminator")
 			}
 
 			str.push(char)
@@ -154,12 +146,12 @@ func parseStr(r reader, ty int) (*JSON5, error) {
 			rawlen := len(str.raw)
 			prev1char := str.raw[rawlen-1]
 			if prev1char != '\\' {
-				return nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeUnterminatedString, "escaped line terminator")
 			}
 
 			prev2char := str.raw[rawlen-2]
 			if prev2char == '\\' {
-				return nil, ErrInvalidFormat
+				return nil, r.syntaxErr(CodeUnterminatedString, "escaped line terminator")
 			}
 
 			continue
@@ -206,11 +198,29 @@ func parseStr(r reader, ty int) (*JSON5, error) {
 		str.push(char)
 	}
 
+	if maxLen > 0 && len(str.raw) > maxLen {
+		return nil, r.limitErr("MaxStringLength", maxLen)
+	}
+
 	str.val = unescapeRunesToStr(str.raw, ty)
 
 	return str, nil
 }
 
+// strReadErr convert io.EOF returned by ReadRune inside string to SyntaxError
+func strReadErr(r *reader, err error, ty int) error {
+	if err != io.EOF {
+		return err
+	}
+
+	quote := `'"'`
+	if ty == singleQuotedStr {
+		quote = `"'"`
+	}
+
+	return newSyntaxError(CodeUnterminatedString, r.pos(), EOFChar, quote)
+}
+
 func unescapeRunesToStr(chars []rune, ty int) string {
 	newRunes := make([]rune, 0)
 	r := bufio.NewReader(bytes.NewReader(runesToUTF8(chars)))
@@ -236,6 +246,15 @@ func unescapeRunesToStr(chars []rune, ty int) string {
 				}
 
 				dec, _ := strconv.ParseInt(string(hexs), 16, 64)
+
+				// join surrogate pair, such as \uD83D\uDE00
+				if n := len(newRunes); n > 0 && utf16.IsSurrogate(rune(dec)) {
+					if pair := utf16.DecodeRune(newRunes[n-1], rune(dec)); pair != unicode.ReplacementChar {
+						newRunes[n-1] = pair
+						continue
+					}
+				}
+
 				newRunes = append(newRunes, rune(dec))
 				continue
 			}
@@ -255,9 +274,9 @@ func unescapeRunesToStr(chars []rune, ty int) string {
 
 			// line terminator (return carriage or return carriage and line feed)
 			if char == '\r' {
-				char, _, _ := r.ReadRune()
-				if char == '\n' {
-					continue
+				char, _, err := r.ReadRune()
+				if err == nil && char != '\n' {
+					r.UnreadRune()
 				}
 
 				continue
@@ -280,6 +299,7 @@ func unescapeRunesToStr(chars []rune, ty int) string {
 
 			// null
 			if char == '0' {
+				newRunes = append(newRunes, 0)
 				continue
 			}
 
@@ -309,7 +329,7 @@ func unescapeRunesToStr(chars []rune, ty int) string {
 
 			// horizontal tab
 			if char == 't' {
-				newRunes = append(newRunes, '\v')
+				newRunes = append(newRunes, '\t')
 				continue
 			}
 
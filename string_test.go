@@ -0,0 +1,38 @@
+package json5extract
+
+import "testing"
+
+func TestUnescape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`'\0'`, "\x00"},
+		{`'a\0b'`, "a\x00b"},
+		{`'\b\f\n\r\t\v'`, "\b\f\n\r\t\v"},
+		{`"\'\"\\\/"`, `'"\/`},
+		{`'\q\A\ '`, "qA "},
+		{`'\x41\x7e'`, "A~"},
+		{`'é中'`, "é中"},
+		{`'😀'`, "😀"},
+		{`'a😀b😀'`, "a😀b😀"},
+		{`'\uD83D'`, "�"},
+		{`'\uDE00\uD83D'`, "��"},
+		{"'a\\\nb'", "ab"},
+		{"'a\\\r\nb'", "ab"},
+		{"'a\\\rb'", "ab"},
+		{"'a\\ b\\ c'", "abc"},
+	}
+
+	for _, test := range tests {
+		json, err := Parse([]byte(test.in))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.in, err)
+			continue
+		}
+
+		if got := json.String(); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.in, got, test.want)
+		}
+	}
+}
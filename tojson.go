@@ -0,0 +1,162 @@
+package json5extract
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NaN and Infinity policies of ToJSON, strict JSON has no such numbers
+const (
+	// NonFiniteError fail with UnsupportedValueError
+	NonFiniteError = iota
+	// NonFiniteNull write null
+	NonFiniteNull
+	// NonFiniteString write string "NaN", "Infinity" or "-Infinity"
+	NonFiniteString
+)
+
+// JSONOptions control JSON written by ToJSON
+type JSONOptions struct {
+	// NonFinite is policy for NaN and Infinity, default is NonFiniteError
+	NonFinite int
+}
+
+// ToJSON write the value as compact strict JSON (RFC 8259). Strings and keys are re-escaped,
+// hexadecimal numbers are written in decimal, explicit '+' sign and leading or trailing decimal
+// point are normalized. Only the first non nil opts is used
+func (json *JSON5) ToJSON(opts ...*JSONOptions) ([]byte, error) {
+	o := new(JSONOptions)
+	for _, opt := range opts {
+		if opt != nil {
+			o = opt
+			break
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := writeJSON(buf, json, o); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON implement json.Marshaler, see ToJSON. NaN and Infinity are an error
+func (json *JSON5) MarshalJSON() ([]byte, error) {
+	return json.ToJSON()
+}
+
+func writeJSON(buf *bytes.Buffer, json *JSON5, opts *JSONOptions) error {
+	switch json.kind {
+	case String:
+		writeQuoted(buf, json.String(), '"')
+	case Integer, Float:
+		str, err := jsonNumber(json.Number())
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(str)
+	case Infinity, NaN:
+		return writeNonFinite(buf, json, opts)
+	case Boolean, Null:
+		buf.WriteString(string(json.raw))
+	case Array:
+		buf.WriteByte('[')
+		for i, val := range json.Array() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeJSON(buf, val, opts); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+	case Object:
+		buf.WriteByte('{')
+		for i, m := range json.Members() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			writeQuoted(buf, m.Key, '"')
+			buf.WriteByte(':')
+			if err := writeJSON(buf, m.Value, opts); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte('}')
+	}
+
+	return nil
+}
+
+func writeNonFinite(buf *bytes.Buffer, json *JSON5, opts *JSONOptions) error {
+	switch opts.NonFinite {
+	case NonFiniteNull:
+		buf.WriteString("null")
+	case NonFiniteString:
+		writeQuoted(buf, nonFiniteName(json), '"')
+	default:
+		return nonFiniteErr(json)
+	}
+
+	return nil
+}
+
+// nonFiniteName return "NaN", "Infinity" or "-Infinity"
+func nonFiniteName(json *JSON5) string {
+	if json.kind == NaN {
+		return "NaN"
+	}
+
+	if json.Number().IsNegative() {
+		return "-Infinity"
+	}
+
+	return "Infinity"
+}
+
+func nonFiniteErr(json *JSON5) error {
+	return &UnsupportedValueError{
+		Value: reflect.ValueOf(json),
+		Msg:   fmt.Sprintf("%s at line %d, column %d", nonFiniteName(json), json.start.Line, json.start.Column),
+	}
+}
+
+// jsonNumber return Integer or Float literal as JSON number
+func jsonNumber(num Number) (string, error) {
+	if num.hex {
+		i, err := num.BigInt()
+		if err != nil {
+			return "", err
+		}
+
+		// keep sign of -0x0
+		if num.negZero {
+			return "-0", nil
+		}
+
+		return i.String(), nil
+	}
+
+	neg, unsigned := num.split()
+	if num.leadingPoint {
+		unsigned = "0" + unsigned
+	}
+
+	if num.trailingPoint {
+		unsigned = strings.Replace(unsigned, ".", "", 1)
+	}
+
+	if neg {
+		return "-" + unsigned, nil
+	}
+
+	return unsigned, nil
+}
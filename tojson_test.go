@@ -0,0 +1,81 @@
+package json5extract
+
+import "testing"
+
+func TestToJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`0x1F`, `31`},
+		{`-0x1f`, `-31`},
+		{`+0XFF`, `255`},
+		{`-0x0`, `-0`},
+		{`0x0`, `0`},
+		{`+1`, `1`},
+		{`+1.5e3`, `1.5e3`},
+		{`.5`, `0.5`},
+		{`-.5`, `-0.5`},
+		{`5.`, `5`},
+		{`-5.e2`, `-5e2`},
+		{`-0`, `-0`},
+		{`0x123456789ABCDEF0123`, `5373003642731685151011`},
+		{`{a: 'x', 'b"c': [+1, .5, "é\0"]}`, `{"a":"x","b\"c":[1,0.5,"é\u0000"]}`},
+	}
+
+	for _, test := range tests {
+		json, err := Parse([]byte(test.in))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.in, err)
+			continue
+		}
+
+		got, err := json.ToJSON()
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.in, err)
+			continue
+		}
+
+		if string(got) != test.want {
+			t.Errorf("%s: got %s, want %s", test.in, got, test.want)
+		}
+	}
+}
+
+func TestToJSONNonFinite(t *testing.T) {
+	tests := []struct {
+		in     string
+		null   string
+		string string
+	}{
+		{`Infinity`, `null`, `"Infinity"`},
+		{`-Infinity`, `null`, `"-Infinity"`},
+		{`+Infinity`, `null`, `"Infinity"`},
+		{`[NaN]`, `[null]`, `["NaN"]`},
+	}
+
+	for _, test := range tests {
+		json, err := Parse([]byte(test.in))
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.in, err)
+			continue
+		}
+
+		// rejected by default, and by MarshalJSON
+		if _, err := json.ToJSON(); err == nil {
+			t.Errorf("%s: expected error", test.in)
+		}
+
+		if _, err := json.MarshalJSON(); err == nil {
+			t.Errorf("%s: MarshalJSON expected error", test.in)
+		}
+
+		if got, err := json.ToJSON(&JSONOptions{NonFinite: NonFiniteNull}); err != nil || string(got) != test.null {
+			t.Errorf("%s: got %s, %v, want %s", test.in, got, err, test.null)
+		}
+
+		if got, err := json.ToJSON(nil, &JSONOptions{NonFinite: NonFiniteString}); err != nil || string(got) != test.string {
+			t.Errorf("%s: got %s, %v, want %s", test.in, got, err, test.string)
+		}
+	}
+}
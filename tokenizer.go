@@ -0,0 +1,226 @@
+package json5extract
+
+import (
+	"io"
+	"unicode"
+)
+
+// Token kinds
+const (
+	// TokenPunct is one of { } [ ] : ,
+	TokenPunct = iota
+	// TokenString is a quoted string
+	TokenString
+	// TokenNumber is a number, Infinity and NaN included
+	TokenNumber
+	// TokenIdentifier is an unquoted identifier, such as an object key
+	TokenIdentifier
+	// TokenLiteral is true, false or null
+	TokenLiteral
+	// TokenComment is a single line comment, without its line terminator, or a multi line comment
+	TokenComment
+	// TokenWhitespace is a run of whitespaces and line terminators
+	TokenWhitespace
+)
+
+// Token is a lexical token of JSON5 text
+type Token struct {
+	Kind int
+	// Raw is the token text as written in the source
+	Raw string
+	// Start is position of the first char, End is position right after the last char
+	Start Position
+	End   Position
+	// Value is decoded token, string for TokenString and TokenIdentifier, Number for TokenNumber,
+	// bool for true and false, nil otherwise
+	Value interface{}
+}
+
+// Tokenizer split JSON5 text into tokens. Tokens are not checked against JSON5 grammar, only
+// each token must be well formed
+type Tokenizer struct {
+	r   *reader
+	err error
+}
+
+// NewTokenizer return Tokenizer reading from rdr
+func NewTokenizer(rdr io.Reader) *Tokenizer {
+	r := newReader(rdr)
+	// number is ended by any char which can not continue it, such as ':' or '/'
+	r.opts = &Options{WordBoundary: true}
+
+	return &Tokenizer{r: r}
+}
+
+// Next return the next token. Return io.EOF at the end of input, SyntaxError if a token is malformed
+// and error from the source otherwise. Once an error is returned, every later call return it
+func (t *Tokenizer) Next() (Token, error) {
+	if t.err != nil {
+		return Token{}, t.err
+	}
+
+	tok, err := t.next()
+	if err != nil {
+		t.err = err
+		return Token{}, err
+	}
+
+	return tok, nil
+}
+
+func (t *Tokenizer) next() (Token, error) {
+	r := t.r
+	// buffer hold only the current token
+	r.release()
+
+	tok := Token{Start: r.pos()}
+	char, _, err := r.ReadRune()
+	if err != nil {
+		if err == io.EOF && r.err() == nil {
+			return Token{}, io.EOF
+		}
+
+		return Token{}, err
+	}
+
+	switch {
+	case isWhitespace(char):
+		tok.Kind = TokenWhitespace
+		if err := skipWhitespace(r); err != nil {
+			return Token{}, err
+		}
+	case char == '{' || char == '}' || char == '[' || char == ']' || char == ':' || char == ',':
+		tok.Kind = TokenPunct
+	case char == '/':
+		tok.Kind = TokenComment
+		typ, err := parseComment(r)
+		if err != nil {
+			return Token{}, err
+		}
+
+		// leave line terminator, which is not read at the end of input
+		if typ == CommentLine && r.canUnread {
+			r.UnreadRune()
+		}
+	case char == '"' || char == '\'':
+		ty := doubleQuotedStr
+		if char == '\'' {
+			ty = singleQuotedStr
+		}
+
+		str, err := parseStr(r, ty)
+		if err != nil {
+			return Token{}, err
+		}
+
+		tok.Kind = TokenString
+		tok.Value = str.val
+	case isCharNumBegin(char) && char != 'I' && char != 'N':
+		num, err := parseNum(r, char)
+		if err != nil {
+			return Token{}, err
+		}
+
+		tok.Kind = TokenNumber
+		tok.Value = num.Number()
+	case char == '\\' || isCharIDValid(char, true):
+		name, err := scanIdentifier(r, char)
+		if err != nil {
+			return Token{}, err
+		}
+
+		tok.Kind = TokenIdentifier
+		tok.Value = name
+	default:
+		return Token{}, r.syntaxErr(CodeUnexpectedChar, "token")
+	}
+
+	tok.End = r.pos()
+	tok.Raw = string(r.buf[:r.i])
+	if tok.Kind == TokenIdentifier {
+		classifyWord(&tok)
+	}
+
+	return tok, nil
+}
+
+// classifyWord change identifier token written as true, false, null, Infinity or NaN to literal or number
+func classifyWord(tok *Token) {
+	switch tok.Raw {
+	case "true", "false":
+		tok.Kind = TokenLiteral
+		tok.Value = tok.Raw == "true"
+	case "null":
+		tok.Kind = TokenLiteral
+		tok.Value = nil
+	case "Infinity", "NaN":
+		kind := Infinity
+		if tok.Raw == "NaN" {
+			kind = NaN
+		}
+
+		tok.Kind = TokenNumber
+		tok.Value = *newNumber(&JSON5{kind: kind, raw: []rune(tok.Raw)}, new(numStates))
+	}
+}
+
+// isWhitespace check if char is JSON5 whitespace or line terminator, control chars included
+func isWhitespace(char rune) bool {
+	return unicode.IsControl(char) || unicode.IsSpace(char) || char == '\uFEFF'
+}
+
+// skipWhitespace read whitespaces following the last rune read
+func skipWhitespace(r *reader) error {
+	for {
+		char, _, err := r.ReadRune()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		if !isWhitespace(char) {
+			r.UnreadRune()
+			return nil
+		}
+	}
+}
+
+// scanIdentifier read identifier name beginning with char, which is the last rune read.
+// Unicode escapes are decoded
+func scanIdentifier(r *reader, char rune) (string, error) {
+	var rs []rune
+	for {
+		if char == '\\' {
+			esc, err := parseUnicode(r)
+			if err != nil {
+				return "", err
+			}
+
+			if !isCharIDValid(esc, len(rs) == 0) {
+				return "", r.syntaxErr(CodeBadEscape, "escaped identifier char")
+			}
+
+			char = esc
+		} else if !isCharIDValid(char, len(rs) == 0) {
+			r.UnreadRune()
+			break
+		}
+
+		rs = append(rs, char)
+
+		var err error
+		char, _, err = r.ReadRune()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			return "", err
+		}
+	}
+
+	return string(rs), nil
+}
@@ -0,0 +1,190 @@
+package json5extract
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// tokenize return every token of in, and the error ending tokenization other than io.EOF
+func tokenize(in string) ([]Token, error) {
+	var toks []Token
+	tk := NewTokenizer(strings.NewReader(in))
+	for {
+		tok, err := tk.Next()
+		if err == io.EOF {
+			return toks, nil
+		}
+
+		if err != nil {
+			return toks, err
+		}
+
+		toks = append(toks, tok)
+	}
+}
+
+func TestTokenizer(t *testing.T) {
+	tests := []struct {
+		in   string
+		kind int
+		// value decoded from the token, Number are compared by String
+		value interface{}
+	}{
+		{`{`, TokenPunct, nil},
+		{`}`, TokenPunct, nil},
+		{`[`, TokenPunct, nil},
+		{`]`, TokenPunct, nil},
+		{`:`, TokenPunct, nil},
+		{`,`, TokenPunct, nil},
+		{`"a\"b"`, TokenString, `a"b`},
+		{`'\x41é\n'`, TokenString, "Aé\n"},
+		{"'a\\\nb'", TokenString, "ab"},
+		{`0`, TokenNumber, "0"},
+		{`-12.5e3`, TokenNumber, "-12.5e3"},
+		{`0x1F`, TokenNumber, "0x1F"},
+		{`+.5`, TokenNumber, "+.5"},
+		{`5.`, TokenNumber, "5."},
+		{`Infinity`, TokenNumber, "Infinity"},
+		{`-Infinity`, TokenNumber, "-Infinity"},
+		{`NaN`, TokenNumber, "NaN"},
+		{`name`, TokenIdentifier, "name"},
+		{`$_é1`, TokenIdentifier, "$_é1"},
+		{`abc`, TokenIdentifier, "abc"},
+		{`Infinite`, TokenIdentifier, "Infinite"},
+		{`true`, TokenLiteral, true},
+		{`false`, TokenLiteral, false},
+		{`null`, TokenLiteral, nil},
+		{`// line`, TokenComment, nil},
+		{`/* multi
+			line */`, TokenComment, nil},
+		{" \t\r\n \uFEFF ", TokenWhitespace, nil},
+	}
+
+	for _, test := range tests {
+		toks, err := tokenize(test.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", test.in, err)
+			continue
+		}
+
+		if len(toks) != 1 {
+			t.Errorf("%q: got %d tokens, want 1", test.in, len(toks))
+			continue
+		}
+
+		tok := toks[0]
+		if tok.Kind != test.kind || tok.Raw != test.in {
+			t.Errorf("%q: got kind %d and raw %q, want kind %d", test.in, tok.Kind, tok.Raw, test.kind)
+		}
+
+		value := tok.Value
+		if num, ok := value.(Number); ok {
+			value = num.String()
+		}
+
+		if value != test.value {
+			t.Errorf("%q: got value %#v, want %#v", test.in, value, test.value)
+		}
+	}
+}
+
+func TestTokenizerSequence(t *testing.T) {
+	in := "{a:1,// c\n 'b': [true]}"
+	want := []struct {
+		kind  int
+		raw   string
+		start [3]int
+		end   [3]int
+	}{
+		{TokenPunct, `{`, [3]int{0, 1, 1}, [3]int{1, 1, 2}},
+		{TokenIdentifier, `a`, [3]int{1, 1, 2}, [3]int{2, 1, 3}},
+		{TokenPunct, `:`, [3]int{2, 1, 3}, [3]int{3, 1, 4}},
+		{TokenNumber, `1`, [3]int{3, 1, 4}, [3]int{4, 1, 5}},
+		{TokenPunct, `,`, [3]int{4, 1, 5}, [3]int{5, 1, 6}},
+		{TokenComment, `// c`, [3]int{5, 1, 6}, [3]int{9, 1, 10}},
+		{TokenWhitespace, "\n ", [3]int{9, 1, 10}, [3]int{11, 2, 2}},
+		{TokenString, `'b'`, [3]int{11, 2, 2}, [3]int{14, 2, 5}},
+		{TokenPunct, `:`, [3]int{14, 2, 5}, [3]int{15, 2, 6}},
+		{TokenWhitespace, ` `, [3]int{15, 2, 6}, [3]int{16, 2, 7}},
+		{TokenPunct, `[`, [3]int{16, 2, 7}, [3]int{17, 2, 8}},
+		{TokenLiteral, `true`, [3]int{17, 2, 8}, [3]int{21, 2, 12}},
+		{TokenPunct, `]`, [3]int{21, 2, 12}, [3]int{22, 2, 13}},
+		{TokenPunct, `}`, [3]int{22, 2, 13}, [3]int{23, 2, 14}},
+	}
+
+	toks, err := tokenize(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(toks) != len(want) {
+		t.Fatalf("got %d tokens, want %d", len(toks), len(want))
+	}
+
+	for i, tok := range toks {
+		w := want[i]
+		start := [3]int{tok.Start.Offset, tok.Start.Line, tok.Start.Column}
+		end := [3]int{tok.End.Offset, tok.End.Line, tok.End.Column}
+		if tok.Kind != w.kind || tok.Raw != w.raw || start != w.start || end != w.end {
+			t.Errorf("token %d: got %d %q %v-%v, want %d %q %v-%v", i, tok.Kind, tok.Raw, start, end, w.kind, w.raw, w.start, w.end)
+		}
+	}
+
+	// raw of every token rebuild the input
+	var raw strings.Builder
+	for _, tok := range toks {
+		raw.WriteString(tok.Raw)
+	}
+
+	if raw.String() != in {
+		t.Errorf("got %q, want %q", raw.String(), in)
+	}
+}
+
+func TestTokenizerErrors(t *testing.T) {
+	tests := []struct {
+		in string
+		// number of tokens before the error
+		tokens int
+		code   int
+		offset int
+	}{
+		{`'abc`, 0, CodeUnterminatedString, 4},
+		{"'a\nb'", 0, CodeUnterminatedString, 2},
+		{`[1, /* open`, 4, CodeUnexpectedEOF, 11},
+		{`@`, 0, CodeUnexpectedChar, 0},
+		{`[0x]`, 1, CodeInvalidNumber, 3},
+		{`1.2.3`, 0, CodeInvalidNumber, 3},
+		{`1a`, 0, CodeInvalidNumber, 1},
+		{`-`, 0, CodeUnexpectedEOF, 1},
+		{`a\u00zz`, 0, CodeBadEscape, 5},
+		{`a\u0020`, 0, CodeBadEscape, 6},
+	}
+
+	for _, test := range tests {
+		tk := NewTokenizer(strings.NewReader(test.in))
+		var err error
+		n := 0
+		for ; err == nil; n++ {
+			_, err = tk.Next()
+		}
+
+		var synErr *SyntaxError
+		if !errors.As(err, &synErr) {
+			t.Errorf("%q: got %v, want SyntaxError", test.in, err)
+			continue
+		}
+
+		if n-1 != test.tokens || synErr.Code != test.code || synErr.Pos.Offset != test.offset {
+			t.Errorf("%q: got %v after %d tokens, want code %d at offset %d after %d tokens",
+				test.in, err, n-1, test.code, test.offset, test.tokens)
+		}
+
+		// error is sticky
+		if _, again := tk.Next(); again != err {
+			t.Errorf("%q: got %v after the error, want %v", test.in, again, err)
+		}
+	}
+}
@@ -1,37 +1,26 @@
 package json5extract
 
-import (
-	"io"
-	"strconv"
-)
+import "strconv"
 
-func parseUnicode(r reader) (rune, error) {
+func parseUnicode(r *reader) (rune, error) {
 	char, _, err := r.ReadRune()
 	if err != nil {
-		if err == io.EOF {
-			return 0, ErrInvalidFormat
-		}
-
-		return 0, err
+		return 0, r.readErr(err, "'u'")
 	}
 
 	if char != 'u' {
-		return 0, ErrInvalidFormat
+		return 0, r.syntaxErr(CodeBadEscape, "'u'")
 	}
 
 	hexs := make([]rune, 4)
 	for i := 0; i < 4; i++ {
 		char, _, err := r.ReadRune()
 		if err != nil {
-			if err == io.EOF {
-				return 0, ErrInvalidFormat
-			}
-
-			return 0, err
+			return 0, r.readErr(err, "hex digit")
 		}
 
 		if !isCharHex(char) {
-			return 0, ErrInvalidFormat
+			return 0, r.syntaxErr(CodeBadEscape, "hex digit")
 		}
 
 		hexs[i] = char
@@ -38,23 +38,6 @@ func removeSliceIndex(s []rune, i int) []rune {
 	return s[:len(s)-1]
 }
 
-func isReservedWord(chars []rune) bool {
-	rl := len(chars)
-	for _, word := range rsvWords {
-		if len(word) == rl {
-			for i, c := range word {
-				if chars[i] != c {
-					return false
-				}
-			}
-
-			return true
-		}
-	}
-
-	return false
-}
-
 func isCharNumBegin(char rune) bool {
 	if unicode.IsNumber(char) {
 		return true
@@ -0,0 +1,76 @@
+package json5extract
+
+import "errors"
+
+var (
+	// SkipChildren is returned by WalkFunc to skip children of the visited value
+	SkipChildren = errors.New("skip children")
+	// Stop is returned by WalkFunc to stop walking, Walk return nil
+	Stop = errors.New("stop walk")
+)
+
+// WalkFunc is called for every visited value, path locate json from the walked value
+type WalkFunc func(path Path, json *JSON5) error
+
+// Walk visit json and its descendants in document order, parents before children. If fn return
+// SkipChildren, children of the value are not visited. If fn return Stop, walking stops and Walk
+// return nil. Any other error stops walking and is returned
+func Walk(json *JSON5, fn WalkFunc) error {
+	return WalkPrePost(json, fn, nil)
+}
+
+// WalkPrePost visit json and its descendants calling pre before visiting children of a value and post
+// after them, either may be nil. SkipChildren returned by pre skip the children, post is still called
+// for the value. SkipChildren returned by post is ignored. See Walk
+func WalkPrePost(json *JSON5, pre, post WalkFunc) error {
+	err := walk(Node{Value: json}, pre, post)
+	if err == Stop {
+		return nil
+	}
+
+	return err
+}
+
+func walk(n Node, pre, post WalkFunc) error {
+	skip := false
+	if pre != nil {
+		if err := pre(n.Path, n.Value); err != nil {
+			if err != SkipChildren {
+				return err
+			}
+
+			skip = true
+		}
+	}
+
+	if !skip {
+		for _, c := range children(n) {
+			if err := walk(c, pre, post); err != nil {
+				return err
+			}
+		}
+	}
+
+	if post != nil {
+		if err := post(n.Path, n.Value); err != nil && err != SkipChildren {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Dotted return path in the form used by DecodeError, such as items[3].id or ["key with space"].
+// Keys which are not identifiers are quoted in brackets. Root path is empty string
+func (p Path) Dotted() string {
+	var path string
+	for _, seg := range p {
+		if seg.IsIndex() {
+			path = pathIndex(path, seg.Index)
+		} else {
+			path = pathKey(path, seg.Key)
+		}
+	}
+
+	return path
+}